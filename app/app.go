@@ -3,12 +3,15 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/rollbar/rollbar-go"
 	log "github.com/sirupsen/logrus"
 
+	ipfuzzing "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing"
 	"github.com/magneticstain/ip-2-cloudresource/resource"
 	platformsearch "github.com/magneticstain/ip-2-cloudresource/search"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
@@ -21,97 +24,358 @@ func GetSupportedPlatforms() []string {
 	return []string{"aws", "gcp", "azure"}
 }
 
-func OutputResults(matchedResource resource.Resource, networkMapping bool, silent bool, jsonOutput bool) {
+func logResource(matchedResource resource.Resource, networkMapping bool) {
+	if matchedResource.Classification != nil {
+		log.Info("IP classified as ", matchedResource.Classification.Description)
+		return
+	}
+
 	acctAliasFmted := strings.Join(matchedResource.AccountAliases, ", ")
 
-	if !silent {
-		if matchedResource.RID != "" {
-			var acctStr string
-			if matchedResource.AccountID == "current" {
-				acctStr = "current account"
-			} else {
-				acctStr = fmt.Sprintf("account [ %s ( %s ) ]", matchedResource.AccountID, acctAliasFmted)
+	var acctStr string
+	if matchedResource.AccountID == "current" {
+		acctStr = "current account"
+	} else {
+		acctStr = fmt.Sprintf("account [ %s ( %s ) ]", matchedResource.AccountID, acctAliasFmted)
+	}
+
+	var regionStr string
+	if matchedResource.Region != "" {
+		regionStr = fmt.Sprintf(" in region [ %s ]", matchedResource.Region)
+	}
+
+	var statusStr string
+	if matchedResource.Status != "" {
+		statusStr = fmt.Sprintf(" with status [ %s ]", matchedResource.Status)
+	}
+
+	log.Info("resource found -> [ ", matchedResource.RID, " ] within ", matchedResource.CloudSvc, " service running in ", acctStr, regionStr, statusStr)
+
+	if networkMapping {
+		var networkMapGraph string
+
+		var networkResourceElmnt string
+		networkMapResourceCnt := len(matchedResource.NetworkMap)
+		for i, networkResource := range matchedResource.NetworkMap {
+			networkResourceElmnt = "%s"
+			if i != networkMapResourceCnt-1 {
+				networkResourceElmnt += " -> "
 			}
 
-			log.Info("resource found -> [ ", matchedResource.RID, " ] within ", matchedResource.CloudSvc, " service running in ", acctStr)
+			networkMapGraph += fmt.Sprintf(networkResourceElmnt, networkResource)
+		}
+
+		log.Info("network map: [ ", networkMapGraph, " ]")
+	}
+
+	if len(matchedResource.DNSRecords) > 0 {
+		var dnsRecordStrs []string
+		for _, dnsRecord := range matchedResource.DNSRecords {
+			dnsRecordStrs = append(dnsRecordStrs, FormatDNSRecord(dnsRecord))
+		}
 
-			if networkMapping {
-				var networkMapGraph string
+		log.Info("DNS records: [ ", strings.Join(dnsRecordStrs, ", "), " ]")
+	}
+}
 
-				var networkResourceElmnt string
-				networkMapResourceCnt := len(matchedResource.NetworkMap)
-				for i, networkResource := range matchedResource.NetworkMap {
-					networkResourceElmnt = "%s"
-					if i != networkMapResourceCnt-1 {
-						networkResourceElmnt += " -> "
-					}
+func FormatDNSRecord(dnsRecord resource.DNSRecord) string {
+	recordType := dnsRecord.Type
+	if dnsRecord.Alias {
+		recordType += " alias"
+	}
 
-					networkMapGraph += fmt.Sprintf(networkResourceElmnt, networkResource)
-				}
+	zoneType := "public"
+	if dnsRecord.PrivateZone {
+		zoneType = "private"
+	}
+
+	return fmt.Sprintf("%s (%s record in %s zone %s)", dnsRecord.FQDN, recordType, zoneType, dnsRecord.ZoneID)
+}
+
+func printResource(matchedResource resource.Resource) {
+	if matchedResource.Classification != nil {
+		fmt.Println(matchedResource.Classification.Description)
+		return
+	}
+
+	fmt.Println(matchedResource.RID)
+	fmt.Printf("%s (%s)", matchedResource.AccountID, strings.Join(matchedResource.AccountAliases, ", "))
+}
 
-				log.Info("network map: [ ", networkMapGraph, " ]")
+func printJSON(data interface{}) {
+	output, err := json.Marshal(data)
+	if err != nil {
+		errMap := map[string]error{"error": err}
+		errMapJSON, _ := json.Marshal(errMap)
+
+		fmt.Printf("%s\n", errMapJSON)
+	} else {
+		fmt.Printf("%s\n", output)
+	}
+}
+
+func isResultFound(matchedResource resource.Resource) bool {
+	return matchedResource.RID != "" || matchedResource.Classification != nil
+}
+
+func OutputResults(matchedResource resource.Resource, networkMapping bool, silent bool, jsonOutput bool) {
+	if !silent {
+		if isResultFound(matchedResource) {
+			logResource(matchedResource, networkMapping)
+		} else {
+			log.Info("resource not found :( better luck next time!")
+		}
+	} else {
+		if jsonOutput {
+			printJSON(matchedResource)
+		} else {
+			// plaintext
+			if isResultFound(matchedResource) {
+				printResource(matchedResource)
+			} else {
+				fmt.Println("not found")
+			}
+		}
+	}
+}
+
+func OutputAllResults(matchedResources []resource.Resource, networkMapping bool, silent bool, jsonOutput bool) {
+	if !silent {
+		if len(matchedResources) > 0 {
+			log.Info(len(matchedResources), " matching resource(s) found")
+
+			for _, matchedResource := range matchedResources {
+				logResource(matchedResource, networkMapping)
 			}
 		} else {
 			log.Info("resource not found :( better luck next time!")
 		}
 	} else {
 		if jsonOutput {
-			output, err := json.Marshal(matchedResource)
-			if err != nil {
-				errMap := map[string]error{"error": err}
-				errMapJSON, _ := json.Marshal(errMap)
+			// always output a list, even if empty, so consumers can rely on the format
+			if matchedResources == nil {
+				matchedResources = []resource.Resource{}
+			}
 
-				fmt.Printf("%s\n", errMapJSON)
+			printJSON(matchedResources)
+		} else {
+			// plaintext
+			if len(matchedResources) > 0 {
+				for _, matchedResource := range matchedResources {
+					printResource(matchedResource)
+					fmt.Println()
+				}
 			} else {
-				fmt.Printf("%s\n", output)
+				fmt.Println("not found")
 			}
+		}
+	}
+}
+
+func OutputBulkResult(result platformsearch.BulkResult, networkMapping bool, silent bool, jsonOutput bool) {
+	if !silent {
+		if len(result.Resources) > 0 {
+			log.Info("results for IP ", result.IpAddr, ":")
+
+			for _, matchedResource := range result.Resources {
+				logResource(matchedResource, networkMapping)
+			}
+		} else {
+			log.Info("resource not found for IP ", result.IpAddr)
+		}
+	} else {
+		if jsonOutput {
+			// one JSON object per line, so results can be consumed as they're streamed
+			if result.Resources == nil {
+				result.Resources = []resource.Resource{}
+			}
+
+			printJSON(result)
 		} else {
 			// plaintext
-			if matchedResource.RID != "" {
-				fmt.Println(matchedResource.RID)
-				fmt.Printf("%s (%s)", matchedResource.AccountID, acctAliasFmted)
+			if len(result.Resources) > 0 {
+				for _, matchedResource := range result.Resources {
+					fmt.Printf("%s ", result.IpAddr)
+					printResource(matchedResource)
+					fmt.Println()
+				}
 			} else {
-				fmt.Println("not found")
+				fmt.Printf("%s not found\n", result.IpAddr)
 			}
 		}
 	}
 }
 
-func RunCloudSearch(platform, tenantID, ipAddr, cloudSvc, orgSearchXaccountRoleARN, orgSearchRoleName, orgSearchOrgUnitID string, ipFuzzing, advIPFuzzing, orgSearch, networkMapping, silent, jsonOutput bool) {
+func readIPAddrsFile(ipAddrsFile string) ([]string, error) {
+	// a path of '-' reads from stdin
+	if ipAddrsFile == "-" {
+		return utils.ReadIPAddrs(os.Stdin)
+	}
+
+	file, err := os.Open(ipAddrsFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck
+
+	return utils.ReadIPAddrs(file)
+}
+
+// SearchOptions holds every setting for a search run, as set via the CLI flags
+type SearchOptions struct {
+	Platform, TenantID, CloudSvc string
+
+	// search targets; only one of these is expected to be set
+	IpAddr, IpAddrsFile, CIDR string
+
+	IPFuzzing, AdvIPFuzzing bool
+	IPRangesFile            string
+	IPRangesCacheTTL        time.Duration
+	IPRangesOffline         bool
+
+	OrgSearch                                                       bool
+	OrgSearchXaccountRoleARN, OrgSearchRoleName, OrgSearchOrgUnitID string
+
+	AWSRegions       string
+	AllMatches       bool
+	NetworkMapping   bool
+	DNSRecords       bool
+	PrivateSearch    bool
+	VpcID            string
+	IpamID           string
+	ConfigAggregator string
+
+	Silent, JSONOutput bool
+}
+
+func newSearch(opts SearchOptions) platformsearch.Search {
+	searchCtlr := platformsearch.Search{
+		Platform:         opts.Platform,
+		TenantID:         opts.TenantID,
+		IpAddr:           opts.IpAddr,
+		AllMatches:       opts.AllMatches,
+		ConfigAggregator: opts.ConfigAggregator,
+		DNSRecordLookup:  opts.DNSRecords,
+		IPRangeSrc: ipfuzzing.IPRangeSource{
+			CacheTTL: opts.IPRangesCacheTTL,
+			FilePath: opts.IPRangesFile,
+			Offline:  opts.IPRangesOffline,
+		},
+		IpamID:        opts.IpamID,
+		PrivateSearch: opts.PrivateSearch,
+		VpcID:         opts.VpcID,
+	}
+	if opts.AWSRegions != "" {
+		searchCtlr.Regions = strings.Split(opts.AWSRegions, ",")
+	}
+
+	return searchCtlr
+}
+
+func RunCloudSearch(opts SearchOptions) {
 	var err error
 
-	platform = strings.ToLower(platform)
+	opts.Platform = strings.ToLower(opts.Platform)
 	supportedPlatforms := GetSupportedPlatforms()
-	if !slices.Contains(supportedPlatforms, platform) {
-		log.Fatal("'", platform, "' is not a supported platform")
+	if !slices.Contains(supportedPlatforms, opts.Platform) {
+		log.Fatal("'", opts.Platform, "' is not a supported platform")
 		return
 	}
 
-	// search
-	log.Info("searching for IP ", ipAddr, " in ", cloudSvc, " ", strings.ToUpper(platform), " service(s)")
+	if opts.PrivateSearch {
+		// private ranges overlap across VPCs and accounts, so the first match is rarely the only one; AWS's IP ranges also only cover public IPs, so there's nothing to fuzz
+		opts.AllMatches = true
+		opts.IPFuzzing = false
+		opts.AdvIPFuzzing = false
 
-	searchCtlr := platformsearch.Search{
-		Platform: platform,
-		TenantID: tenantID,
-		IpAddr:   ipAddr,
+		if opts.VpcID != "" {
+			log.Info("limiting private IP search to VPC ", opts.VpcID)
+		}
+	}
+
+	searchCtlr := newSearch(opts)
+
+	if opts.IpamID != "" {
+		log.Info("using IPAM ", opts.IpamID, " to resolve IPs before searching each service")
+	}
+
+	if opts.ConfigAggregator != "" {
+		log.Info("using AWS Config aggregator ", opts.ConfigAggregator, " in place of searching each account")
+	}
+
+	if opts.IpAddrsFile != "" || opts.CIDR != "" {
+		var ipAddrs []string
+
+		if opts.CIDR != "" {
+			opts.CIDR, err = utils.NormalizeCIDR(opts.CIDR)
+			if err != nil {
+				log.Fatal("invalid CIDR block provided: ", err)
+				return
+			}
+
+			// a CIDR block can map to any number of resources across any service, so fuzzing a single IP doesn't help narrow the search
+			ipAddrs = []string{opts.CIDR}
+			searchCtlr.AllMatches = true
+			opts.IPFuzzing = false
+			opts.AdvIPFuzzing = false
+
+			log.Info("searching for IPs within ", opts.CIDR, " in ", opts.CloudSvc, " ", strings.ToUpper(opts.Platform), " service(s)")
+		} else {
+			ipAddrs, err = readIPAddrsFile(opts.IpAddrsFile)
+			if err != nil {
+				log.Fatal("unable to read IPs to search for: ", err)
+				return
+			}
+
+			log.Info("searching for ", len(ipAddrs), " IP(s) in ", opts.CloudSvc, " ", strings.ToUpper(opts.Platform), " service(s)")
+		}
+
+		// search
+
+		err = searchCtlr.StartBulkSearch(
+			ipAddrs,
+			opts.CloudSvc,
+			opts.IPFuzzing,
+			opts.AdvIPFuzzing,
+			opts.OrgSearch,
+			opts.OrgSearchXaccountRoleARN,
+			opts.OrgSearchRoleName,
+			opts.OrgSearchOrgUnitID,
+			opts.NetworkMapping,
+			func(result platformsearch.BulkResult) {
+				OutputBulkResult(result, opts.NetworkMapping, opts.Silent, opts.JSONOutput)
+			},
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return
 	}
 
+	// search
+	log.Info("searching for IP ", opts.IpAddr, " in ", opts.CloudSvc, " ", strings.ToUpper(opts.Platform), " service(s)")
+
 	_, err = searchCtlr.StartSearch(
-		cloudSvc,
-		ipFuzzing,
-		advIPFuzzing,
-		orgSearch,
-		orgSearchXaccountRoleARN,
-		orgSearchRoleName,
-		orgSearchOrgUnitID,
-		networkMapping,
+		opts.CloudSvc,
+		opts.IPFuzzing,
+		opts.AdvIPFuzzing,
+		opts.OrgSearch,
+		opts.OrgSearchXaccountRoleARN,
+		opts.OrgSearchRoleName,
+		opts.OrgSearchOrgUnitID,
+		opts.NetworkMapping,
 	)
 	if err != nil {
 		log.Fatal(err)
 		return
 	}
 
-	OutputResults(searchCtlr.MatchedResource, networkMapping, silent, jsonOutput)
+	if opts.AllMatches {
+		OutputAllResults(searchCtlr.MatchedResources, opts.NetworkMapping, opts.Silent, opts.JSONOutput)
+	} else {
+		OutputResults(searchCtlr.MatchedResource, opts.NetworkMapping, opts.Silent, opts.JSONOutput)
+	}
 }
 
 func InitRollbar() {
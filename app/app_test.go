@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/magneticstain/ip-2-cloudresource/resource"
 )
@@ -55,3 +57,121 @@ func TestOutputResults_JSON(t *testing.T) {
 		t.Fatalf("expected RID in output; got %s", out)
 	}
 }
+
+func TestOutputResults_JSONClassification(t *testing.T) {
+	rPipe, wPipe, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	old := os.Stdout
+	os.Stdout = wPipe
+	defer func() { os.Stdout = old }()
+
+	r := resource.Resource{
+		Classification: &resource.Classification{
+			Type:        "aws_managed_shared_endpoint",
+			Description: "AWS-managed shared endpoint (S3 in us-east-1), cannot map to customer resource",
+			CloudSvc:    "s3",
+			Region:      "us-east-1",
+		},
+	}
+
+	OutputResults(r, false, true, true)
+
+	_ = wPipe.Close()
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, rPipe)
+	out := strings.TrimSpace(buf.String())
+	if !strings.Contains(out, `"Type":"aws_managed_shared_endpoint"`) {
+		t.Fatalf("expected classification in output; got %s", out)
+	}
+}
+
+func TestOutputAllResults_JSON(t *testing.T) {
+	var tests = []struct {
+		testName       string
+		resources      []resource.Resource
+		expectedOutput string
+	}{
+		{"noMatches", nil, "[]"},
+		{"multipleMatches", []resource.Resource{{RID: "r-123"}, {RID: "r-456"}}, `"RID":"r-456"`},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			rPipe, wPipe, err := os.Pipe()
+			if err != nil {
+				t.Fatalf("failed to create pipe: %v", err)
+			}
+			old := os.Stdout
+			os.Stdout = wPipe
+			defer func() { os.Stdout = old }()
+
+			OutputAllResults(td.resources, false, true, true)
+
+			_ = wPipe.Close()
+			var buf bytes.Buffer
+			_, _ = io.Copy(&buf, rPipe)
+			out := strings.TrimSpace(buf.String())
+			if !strings.HasPrefix(out, "[") || !strings.Contains(out, td.expectedOutput) {
+				t.Fatalf("expected JSON list containing %s; got %s", td.expectedOutput, out)
+			}
+		})
+	}
+}
+
+func TestFormatDNSRecord(t *testing.T) {
+	tests := []struct {
+		dnsRecord resource.DNSRecord
+		want      string
+	}{
+		{resource.DNSRecord{FQDN: "www.example.com", Type: "A", ZoneID: "Z0123"}, "www.example.com (A record in public zone Z0123)"},
+		{resource.DNSRecord{FQDN: "api.internal.example.com", Type: "AAAA", ZoneID: "Z4567", Alias: true, PrivateZone: true}, "api.internal.example.com (AAAA alias record in private zone Z4567)"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDNSRecord(tt.dnsRecord); got != tt.want {
+			t.Fatalf("expected %s got %s", tt.want, got)
+		}
+	}
+}
+
+func TestNewSearch(t *testing.T) {
+	opts := SearchOptions{
+		Platform:         "aws",
+		TenantID:         "tenant-1",
+		IpAddr:           "10.0.0.1",
+		IPRangesFile:     "ip-ranges.json",
+		IPRangesCacheTTL: time.Hour,
+		IPRangesOffline:  true,
+		AWSRegions:       "us-east-1,eu-west-1",
+		AllMatches:       true,
+		DNSRecords:       true,
+		PrivateSearch:    true,
+		VpcID:            "vpc-123",
+		IpamID:           "ipam-123",
+		ConfigAggregator: "org-aggregator",
+	}
+
+	got := newSearch(opts)
+
+	if got.Platform != opts.Platform || got.TenantID != opts.TenantID || got.IpAddr != opts.IpAddr {
+		t.Fatalf("expected platform, tenant, and IP to be copied from options; got %s, %s, %s", got.Platform, got.TenantID, got.IpAddr)
+	}
+	if !got.AllMatches || !got.DNSRecordLookup || !got.PrivateSearch {
+		t.Fatalf("expected all-matches, DNS record lookup, and private search to be enabled")
+	}
+	if got.VpcID != opts.VpcID || got.IpamID != opts.IpamID || got.ConfigAggregator != opts.ConfigAggregator {
+		t.Fatalf("expected VPC, IPAM, and Config aggregator to be copied from options; got %s, %s, %s", got.VpcID, got.IpamID, got.ConfigAggregator)
+	}
+	if got.IPRangeSrc.FilePath != opts.IPRangesFile || got.IPRangeSrc.CacheTTL != opts.IPRangesCacheTTL || !got.IPRangeSrc.Offline {
+		t.Fatalf("expected IP range source to be built from options; got %+v", got.IPRangeSrc)
+	}
+	if want := []string{"us-east-1", "eu-west-1"}; !slices.Equal(got.Regions, want) {
+		t.Fatalf("expected regions %v got %v", want, got.Regions)
+	}
+
+	if got := newSearch(SearchOptions{Platform: "aws"}); got.Regions != nil {
+		t.Fatalf("expected no regions when none are set; got %v", got.Regions)
+	}
+}
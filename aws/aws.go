@@ -2,20 +2,38 @@ package aws
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
 	log "github.com/sirupsen/logrus"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
-	cfp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/cloudfront"
+	// service plugins register themselves with the plugin registry when imported
+	_ "github.com/magneticstain/ip-2-cloudresource/aws/plugin/apigateway"
+	_ "github.com/magneticstain/ip-2-cloudresource/aws/plugin/cloudfront"
+	configp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/config"
 	ec2p "github.com/magneticstain/ip-2-cloudresource/aws/plugin/ec2"
-	elbp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/elb"
+	_ "github.com/magneticstain/ip-2-cloudresource/aws/plugin/ecs"
+	_ "github.com/magneticstain/ip-2-cloudresource/aws/plugin/elb"
+	_ "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eni"
+	_ "github.com/magneticstain/ip-2-cloudresource/aws/plugin/globalaccelerator"
+	ipamp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/ipam"
+	_ "github.com/magneticstain/ip-2-cloudresource/aws/plugin/lightsail"
 	orgp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/organizations"
+	_ "github.com/magneticstain/ip-2-cloudresource/aws/plugin/rds"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 type AWSController struct {
 	PrincipalAWSConn awsconnector.AWSConnector
+	Regions          []string
+	PrivateSearch    bool
+	VpcID            string
+	IpamID           string
+	ConfigAggregator string
 }
 
 func New() (AWSController, error) {
@@ -27,12 +45,7 @@ func New() (AWSController, error) {
 }
 
 func GetSupportedSvcs() []string {
-	return []string{
-		"cloudfront",
-		"ec2",
-		"elbv1",
-		"elbv2",
-	}
+	return registry.GetSupportedSvcs("aws")
 }
 
 func (awsCtrlr AWSController) FetchOrgAcctIds(orgSearchOrgUnitID string, orgSearchXaccountRoleARN string) ([]string, error) {
@@ -69,40 +82,196 @@ func (awsCtrlr AWSController) FetchOrgAcctIds(orgSearchOrgUnitID string, orgSear
 	return acctIds, nil
 }
 
-func (awsCtrlr *AWSController) SearchAWSSvc(ipAddr, cloudSvc string, doNetMapping bool) (generalResource.Resource, error) {
-	var matchingResource generalResource.Resource
-	var err error
+func (awsCtrlr *AWSController) LoadRegions() error {
+	// regions explicitly provided by the user take precedence over the enabled region set
+	if len(awsCtrlr.Regions) > 0 {
+		return nil
+	}
 
-	log.Debug("searching ", cloudSvc, " in AWS controller")
+	ec2p := ec2p.EC2Plugin{AwsConn: awsCtrlr.PrincipalAWSConn}
+	regions, err := ec2p.GetEnabledRegions()
+	if err != nil {
+		return err
+	}
 
-	switch cloudSvc {
-	case "cloudfront":
-		pluginConn := cfp.CloudfrontPlugin{AwsConn: awsCtrlr.PrincipalAWSConn, NetworkMapping: doNetMapping}
-		matchingResource, err = pluginConn.SearchResources(ipAddr)
-		if err != nil {
-			return matchingResource, err
-		}
-	case "ec2":
-		pluginConn := ec2p.EC2Plugin{AwsConn: awsCtrlr.PrincipalAWSConn, NetworkMapping: doNetMapping}
-		matchingResource, err = pluginConn.SearchResources(ipAddr)
-		if err != nil {
-			return matchingResource, err
+	log.Debug("enabled AWS regions found: ", regions)
+	awsCtrlr.Regions = regions
+
+	return nil
+}
+
+func PrioritizeRegions(regions []string, regionHint string) [][]string {
+	// split the regions into search passes: the hinted region first, then the rest as a fallback if nothing is found there
+	if regionHint == "" || (len(regions) > 0 && !slices.Contains(regions, regionHint)) {
+		return [][]string{regions}
+	}
+
+	regionPasses := [][]string{{regionHint}}
+
+	if len(regions) == 0 {
+		// regions couldn't be loaded, so fall back to the default region
+		return append(regionPasses, nil)
+	}
+
+	var remainingRegions []string
+	for _, region := range regions {
+		if region != regionHint {
+			remainingRegions = append(remainingRegions, region)
 		}
-	case "elbv1": // classic ELBs
-		pluginConn := elbp.ELBv1Plugin{AwsConn: awsCtrlr.PrincipalAWSConn, NetworkMapping: doNetMapping}
-		matchingResource, err = pluginConn.SearchResources(ipAddr)
-		if err != nil {
-			return matchingResource, err
+	}
+
+	if len(remainingRegions) > 0 {
+		regionPasses = append(regionPasses, remainingRegions)
+	}
+
+	return regionPasses
+}
+
+func (awsCtrlr *AWSController) searchRegion(ipAddrs []string, cloudSvc, region string, doNetMapping bool) (map[string][]generalResource.Resource, error) {
+	pluginConn, err := registry.NewPlugin("aws", cloudSvc, registry.PluginConfig{
+		PlatformConfig: awsconnector.PluginConfig{
+			AWSConn:       awsCtrlr.PrincipalAWSConn.WithRegion(region),
+			PrivateSearch: awsCtrlr.PrivateSearch,
+			VpcID:         awsCtrlr.VpcID,
+		},
+		NetworkMapping: doNetMapping,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matchingResources, err := registry.SearchResourcesBulk(pluginConn, ipAddrs)
+	if err != nil {
+		return matchingResources, fmt.Errorf("%s search in %s failed: %w", cloudSvc, region, err)
+	}
+
+	for _, ipResources := range matchingResources {
+		for i := range ipResources {
+			ipResources[i].Region = region
 		}
-	case "elbv2":
-		pluginConn := elbp.ELBPlugin{AwsConn: awsCtrlr.PrincipalAWSConn, NetworkMapping: doNetMapping}
-		matchingResource, err = pluginConn.SearchResources(ipAddr)
-		if err != nil {
-			return matchingResource, err
+	}
+
+	return matchingResources, nil
+}
+
+// SearchAWSSvcBulk fetches the service's inventory once per region and returns every resource associated with each of the IPs
+func (awsCtrlr *AWSController) SearchAWSSvcBulk(ipAddrs []string, cloudSvc string, doNetMapping bool) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	log.Debug("searching ", cloudSvc, " in AWS controller")
+
+	pluginConn, err := registry.NewPlugin("aws", cloudSvc, registry.PluginConfig{
+		PlatformConfig: awsconnector.PluginConfig{
+			AWSConn:       awsCtrlr.PrincipalAWSConn,
+			PrivateSearch: awsCtrlr.PrivateSearch,
+			VpcID:         awsCtrlr.VpcID,
+		},
+		NetworkMapping: doNetMapping,
+	})
+	if err != nil {
+		return matchingResources, err
+	}
+
+	if registry.IsGlobalSvc("aws", cloudSvc) {
+		return registry.SearchResourcesBulk(pluginConn, ipAddrs)
+	}
+
+	regions := awsCtrlr.Regions
+	if len(regions) == 0 {
+		// no regions loaded, so fall back to whatever region the environment defaults to
+		regions = []string{awsCtrlr.PrincipalAWSConn.AwsConfig.Region}
+	}
+
+	// search each region concurrently
+	type regionResult struct {
+		resources map[string][]generalResource.Resource
+		err       error
+	}
+
+	regionResults := make(chan regionResult, len(regions))
+	var wg sync.WaitGroup
+
+	for _, region := range regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+
+			log.Debug("searching ", cloudSvc, " in ", region)
+
+			resources, err := awsCtrlr.searchRegion(ipAddrs, cloudSvc, region, doNetMapping)
+			regionResults <- regionResult{resources: resources, err: err}
+		}(region)
+	}
+
+	wg.Wait()
+	close(regionResults)
+
+	var regionErrs []error
+	for result := range regionResults {
+		if result.err != nil {
+			// some regions may be blocked by SCPs or otherwise unreachable, which shouldn't sink the search in every other region
+			log.Warn("error when searching region: ", result.err)
+			regionErrs = append(regionErrs, result.err)
+		} else {
+			for ipAddr, ipResources := range result.resources {
+				matchingResources[ipAddr] = append(matchingResources[ipAddr], ipResources...)
+			}
 		}
-	default:
-		return matchingResource, errors.New("invalid cloud service provided for AWS search")
 	}
 
-	return matchingResource, nil
+	if len(regionErrs) == len(regions) {
+		return matchingResources, errors.Join(regionErrs...)
+	}
+
+	return matchingResources, nil
+}
+
+// SearchIPAM resolves the IPs using the configured IPAM, which covers every account and region it monitors in a single pass
+func (awsCtrlr *AWSController) SearchIPAM(ipAddrs []string, doNetMapping bool) (map[string][]generalResource.Resource, error) {
+	log.Debug("searching IPAM ", awsCtrlr.IpamID, " in AWS controller")
+
+	ipamp := ipamp.IPAMPlugin{
+		AwsConn:        awsCtrlr.PrincipalAWSConn,
+		IpamID:         awsCtrlr.IpamID,
+		NetworkMapping: doNetMapping,
+		PrivateSearch:  awsCtrlr.PrivateSearch,
+		VpcID:          awsCtrlr.VpcID,
+		Regions:        awsCtrlr.Regions,
+	}
+
+	return ipamp.SearchResourcesBulk(ipAddrs)
+}
+
+// SearchConfigAggregator resolves the IPs using the resources recorded by the configured AWS Config aggregator, which covers every account and region it collects from
+func (awsCtrlr *AWSController) SearchConfigAggregator(ipAddrs []string, doNetMapping bool) (map[string][]generalResource.Resource, error) {
+	log.Debug("searching AWS Config aggregator ", awsCtrlr.ConfigAggregator, " in AWS controller")
+
+	configp := configp.ConfigPlugin{
+		AwsConn:        awsCtrlr.PrincipalAWSConn,
+		AggregatorName: awsCtrlr.ConfigAggregator,
+		NetworkMapping: doNetMapping,
+		PrivateSearch:  awsCtrlr.PrivateSearch,
+		VpcID:          awsCtrlr.VpcID,
+		Regions:        awsCtrlr.Regions,
+	}
+
+	return configp.SearchResourcesBulk(ipAddrs)
+}
+
+func (awsCtrlr *AWSController) SearchAWSSvc(ipAddr, cloudSvc string, doNetMapping bool) (generalResource.Resource, error) {
+	var matchingResource generalResource.Resource
+
+	matchingResources, err := awsCtrlr.SearchAWSSvcAll(ipAddr, cloudSvc, doNetMapping)
+	if len(matchingResources) > 0 {
+		matchingResource = matchingResources[0]
+	}
+
+	return matchingResource, err
+}
+
+// SearchAWSSvcAll returns every resource within the service that's associated with the IP, across all regions being searched
+func (awsCtrlr *AWSController) SearchAWSSvcAll(ipAddr, cloudSvc string, doNetMapping bool) ([]generalResource.Resource, error) {
+	matchingResources, err := awsCtrlr.SearchAWSSvcBulk([]string{ipAddr}, cloudSvc, doNetMapping)
+
+	return matchingResources[ipAddr], err
 }
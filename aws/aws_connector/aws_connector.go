@@ -13,6 +13,21 @@ type AWSConnector struct {
 	AwsConfig aws.Config
 }
 
+// PluginConfig is what the AWS controller passes to AWS plugins as the registry's platform config
+type PluginConfig struct {
+	AWSConn AWSConnector
+	// when set, plugins match private IPs instead of public ones, optionally scoped to a single VPC
+	PrivateSearch bool
+	VpcID         string
+}
+
+// PluginConfigFrom pulls the AWS plugin config out of the registry's platform config, returning an empty config if it wasn't set by the AWS controller
+func PluginConfigFrom(platformCfg any) PluginConfig {
+	pluginCfg, _ := platformCfg.(PluginConfig)
+
+	return pluginCfg
+}
+
 func New() (AWSConnector, error) {
 	cfg, err := ConnectToAWS("", aws.Config{})
 
@@ -56,3 +71,11 @@ func ConnectToAWS(roleArn string, baseConfig aws.Config) (aws.Config, error) {
 
 	return cfg, nil
 }
+
+func (ac AWSConnector) WithRegion(region string) AWSConnector {
+	// generate a copy of the connector that targets the given region while reusing the same credentials
+	cfg := ac.AwsConfig.Copy()
+	cfg.Region = region
+
+	return AWSConnector{AwsConfig: cfg}
+}
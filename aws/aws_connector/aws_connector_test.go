@@ -41,3 +41,58 @@ func TestNewAWSConnectorAssumeRole(t *testing.T) {
 		})
 	}
 }
+
+func TestWithRegion(t *testing.T) {
+	var tests = []struct {
+		baseRegion, tgtRegion string
+	}{
+		{"us-east-1", "eu-west-1"},
+		{"us-east-1", "us-east-1"},
+		{"", "ap-southeast-2"},
+	}
+
+	for _, td := range tests {
+		testName := td.tgtRegion
+
+		t.Run(testName, func(t *testing.T) {
+			ac := awsconnector.AWSConnector{AwsConfig: aws.Config{Region: td.baseRegion}}
+
+			regionalAc := ac.WithRegion(td.tgtRegion)
+
+			if regionalAc.AwsConfig.Region != td.tgtRegion {
+				t.Errorf("AWS connector region override failed; wanted %s, received %s", td.tgtRegion, regionalAc.AwsConfig.Region)
+			}
+			if ac.AwsConfig.Region != td.baseRegion {
+				t.Errorf("AWS connector region override modified the base connector; wanted %s, received %s", td.baseRegion, ac.AwsConfig.Region)
+			}
+		})
+	}
+}
+
+func TestPluginConfigFrom(t *testing.T) {
+	pluginCfg := awsconnector.PluginConfig{
+		AWSConn:       awsconnector.AWSConnector{AwsConfig: aws.Config{Region: "us-east-1"}},
+		PrivateSearch: true,
+		VpcID:         "vpc-123",
+	}
+
+	var tests = []struct {
+		testName    string
+		platformCfg any
+		expectedCfg awsconnector.PluginConfig
+	}{
+		{"awsPluginConfig", pluginCfg, pluginCfg},
+		{"noPlatformConfig", nil, awsconnector.PluginConfig{}},
+		{"otherPlatformConfig", "not_an_aws_config", awsconnector.PluginConfig{}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			res := awsconnector.PluginConfigFrom(td.platformCfg)
+
+			if res.AWSConn.AwsConfig.Region != td.expectedCfg.AWSConn.AwsConfig.Region || res.PrivateSearch != td.expectedCfg.PrivateSearch || res.VpcID != td.expectedCfg.VpcID {
+				t.Errorf("Pulling AWS plugin config from platform config failed; expected %+v, received %+v", td.expectedCfg, res)
+			}
+		})
+	}
+}
@@ -91,3 +91,108 @@ func TestSearchAWSSvc_UnknownCloudSvc(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchAWSSvcAll_UnknownCloudSvc(t *testing.T) {
+	var tests = []struct {
+		cloudSvc, ipAddr string
+	}{
+		{"magic_svc", "1.1.1.1"},
+		{"iam", "1.1.1.1"},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("%s_%s", td.cloudSvc, td.ipAddr)
+
+		ac := awsControllerFactory()
+
+		t.Run(testName, func(t *testing.T) {
+			res, err := ac.SearchAWSSvcAll(td.ipAddr, td.cloudSvc, false)
+			if err == nil {
+				t.Errorf("Error was expected, but not seen, when searching for all matches; using %s for unknown cloud service key", td.cloudSvc)
+			}
+
+			if len(res) != 0 {
+				t.Errorf("No resources were expected when searching for all matches in an unknown service, received %d", len(res))
+			}
+		})
+	}
+}
+
+func TestLoadRegions_UserProvided(t *testing.T) {
+	var tests = []struct {
+		regions []string
+	}{
+		{[]string{"us-east-1"}},
+		{[]string{"us-east-1", "eu-west-1", "ap-southeast-2"}},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("%v", td.regions)
+
+		ac := awsControllerFactory()
+		ac.Regions = td.regions
+
+		t.Run(testName, func(t *testing.T) {
+			err := ac.LoadRegions()
+			if err != nil {
+				t.Errorf("unexpected error received when loading user-provided AWS regions: %s", err)
+			}
+
+			if !reflect.DeepEqual(ac.Regions, td.regions) {
+				t.Errorf("AWS region load overrode user-provided regions; expected %v, received %v", td.regions, ac.Regions)
+			}
+		})
+	}
+}
+
+func TestSearchAWSSvc_MultiRegion(t *testing.T) {
+	var tests = []struct {
+		cloudSvc, ipAddr string
+	}{
+		{"ec2", "1.1.1.1"},
+		{"elbv2", "1.1.1.1"},
+		{"cloudfront", "1.1.1.1"},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("%s_%s", td.cloudSvc, td.ipAddr)
+
+		ac := awsControllerFactory()
+		ac.Regions = []string{"us-east-1", "eu-west-1"}
+
+		t.Run(testName, func(t *testing.T) {
+			res, _ := ac.SearchAWSSvc(td.ipAddr, td.cloudSvc, false)
+
+			resType := reflect.TypeOf(res)
+			expectedType := "Resource"
+			if resType.Name() != expectedType {
+				t.Errorf("AWS multi-region resource search failed; expected %s after search, received %s", expectedType, resType.Name())
+			}
+		})
+	}
+}
+
+func TestPrioritizeRegions(t *testing.T) {
+	var tests = []struct {
+		testName           string
+		regions            []string
+		regionHint         string
+		expectedRegionPass [][]string
+	}{
+		{"noHint", []string{"us-east-1", "eu-west-1"}, "", [][]string{{"us-east-1", "eu-west-1"}}},
+		{"hintInRegions", []string{"us-east-1", "eu-west-1", "us-west-2"}, "eu-west-1", [][]string{{"eu-west-1"}, {"us-east-1", "us-west-2"}}},
+		{"hintOnlyRegion", []string{"eu-west-1"}, "eu-west-1", [][]string{{"eu-west-1"}}},
+		{"hintNotInRegions", []string{"us-east-1"}, "eu-west-1", [][]string{{"us-east-1"}}},
+		{"noRegionsLoaded", nil, "eu-west-1", [][]string{{"eu-west-1"}, nil}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			res := awscontroller.PrioritizeRegions(td.regions, td.regionHint)
+
+			if !reflect.DeepEqual(res, td.expectedRegionPass) {
+				t.Errorf("AWS region prioritization failed; expected %v, received %v", td.expectedRegionPass, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	v2types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	enip "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eni"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+type APIGatewayPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	NetworkMapping bool
+}
+
+func init() {
+	// REST (v1) APIs, HTTP and WebSocket (v2) APIs, and the custom domain names mapped to them
+	registry.Register("aws", "apigateway", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return APIGatewayPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping}
+	})
+}
+
+func BuildAPIGatewayARN(region, resourcePath string) string {
+	// API Gateway ARNs don't include an account ID, e.g. arn:aws:apigateway:us-east-1::/restapis/a1b2c3d4e5
+	return arn.ARN{
+		Partition: enip.GetPartition(region),
+		Service:   "apigateway",
+		Region:    region,
+		Resource:  resourcePath,
+	}.String()
+}
+
+func GetRestAPIEndpoint(restAPIID, region string) string {
+	// unlike v2 APIs, REST APIs don't return their default endpoint, so it's built from the API ID
+	endpoint := fmt.Sprintf("%s.execute-api.%s.amazonaws.com", restAPIID, region)
+	if enip.GetPartition(region) == "aws-cn" {
+		endpoint += ".cn"
+	}
+
+	return endpoint
+}
+
+func GetAPIEndpointHost(apiEndpoint string) string {
+	// v2 API endpoints are returned as URLs, e.g. wss://a1b2c3d4e5.execute-api.us-east-1.amazonaws.com
+	parsedEndpoint, err := url.Parse(apiEndpoint)
+	if err != nil {
+		return ""
+	}
+
+	return parsedEndpoint.Hostname()
+}
+
+func IsPrivateRestAPI(restAPI types.RestApi) bool {
+	// private APIs are only reachable through interface VPC endpoints, so they never have public IPs of their own
+	return restAPI.EndpointConfiguration != nil && slices.Contains(restAPI.EndpointConfiguration.Types, types.EndpointTypePrivate)
+}
+
+func resolveDNSName(fqdn string) []string {
+	if fqdn == "" {
+		return nil
+	}
+
+	ipAddrs, err := utils.LookupFQDN(fqdn)
+	if err != nil {
+		log.Debug("unable to resolve API Gateway domain name ", fqdn, ": ", err)
+		return nil
+	}
+
+	return utils.FormatIPAddrs(ipAddrs)
+}
+
+func (apigwp APIGatewayPlugin) GetResources() ([]types.RestApi, error) {
+	var restAPIs []types.RestApi
+
+	apigwClient := apigateway.NewFromConfig(apigwp.AwsConn.AwsConfig)
+	paginator := apigateway.NewGetRestApisPaginator(apigwClient, &apigateway.GetRestApisInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return restAPIs, err
+		}
+
+		restAPIs = append(restAPIs, output.Items...)
+	}
+
+	return restAPIs, nil
+}
+
+func (apigwp APIGatewayPlugin) GetV2APIs() ([]v2types.Api, error) {
+	var apis []v2types.Api
+	var nextToken *string
+
+	apigwClient := apigatewayv2.NewFromConfig(apigwp.AwsConn.AwsConfig)
+
+	for {
+		output, err := apigwClient.GetApis(context.TODO(), &apigatewayv2.GetApisInput{NextToken: nextToken})
+		if err != nil {
+			return apis, err
+		}
+
+		apis = append(apis, output.Items...)
+
+		// the v2 API doesn't provide paginators, so tokens need to be followed manually
+		nextToken = output.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return apis, nil
+}
+
+func (apigwp APIGatewayPlugin) GetRestAPIStages(restAPIID string) ([]string, error) {
+	var stageNames []string
+
+	apigwClient := apigateway.NewFromConfig(apigwp.AwsConn.AwsConfig)
+
+	output, err := apigwClient.GetStages(context.TODO(), &apigateway.GetStagesInput{RestApiId: &restAPIID})
+	if err != nil {
+		return stageNames, err
+	}
+
+	for _, stage := range output.Item {
+		stageNames = append(stageNames, aws.ToString(stage.StageName))
+	}
+
+	return stageNames, nil
+}
+
+func (apigwp APIGatewayPlugin) GetV2APIStages(apiID string) ([]string, error) {
+	var stageNames []string
+	var nextToken *string
+
+	apigwClient := apigatewayv2.NewFromConfig(apigwp.AwsConn.AwsConfig)
+
+	for {
+		output, err := apigwClient.GetStages(context.TODO(), &apigatewayv2.GetStagesInput{ApiId: &apiID, NextToken: nextToken})
+		if err != nil {
+			return stageNames, err
+		}
+
+		for _, stage := range output.Items {
+			stageNames = append(stageNames, aws.ToString(stage.StageName))
+		}
+
+		nextToken = output.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return stageNames, nil
+}
+
+func (apigwp APIGatewayPlugin) addAPIDetails(matchingResource *generalResource.Resource, apiEndpoint string, stageNames []string) {
+	matchingResource.AddAttribute("ApiEndpoint", apiEndpoint)
+	matchingResource.AddAttribute("Stages", strings.Join(stageNames, ","))
+
+	if apigwp.NetworkMapping {
+		matchingResource.NetworkMap = append(matchingResource.NetworkMap, apiEndpoint, matchingResource.Id, utils.FormatStrSliceAsCSV(stageNames))
+	}
+}
+
+func (apigwp APIGatewayPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+	region := apigwp.AwsConn.AwsConfig.Region
+
+	restAPIs, err := apigwp.GetResources()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	v2APIs, err := apigwp.GetV2APIs()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	domainNames, err := apigwp.GetDomainNames()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	for _, restAPI := range restAPIs {
+		if restAPI.DisableExecuteApiEndpoint || IsPrivateRestAPI(restAPI) {
+			continue
+		}
+
+		restAPIEndpoint := GetRestAPIEndpoint(aws.ToString(restAPI.Id), region)
+		restAPIIPAddrs := resolveDNSName(restAPIEndpoint)
+
+		for _, tgt := range ipMatcher.MatchAny(restAPIIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(restAPI.Id)
+			matchingResource.RID = BuildAPIGatewayARN(region, "/restapis/"+matchingResource.Id)
+			matchingResource.Name = aws.ToString(restAPI.Name)
+			matchingResource.CloudSvc = "apigateway"
+			matchingResource.Status = string(restAPI.ApiStatus)
+			matchingResource.Tags = restAPI.Tags
+
+			matchingResource.AddAttribute("ProtocolType", "REST")
+			if restAPI.EndpointConfiguration != nil {
+				var endpointTypes []string
+				for _, endpointType := range restAPI.EndpointConfiguration.Types {
+					endpointTypes = append(endpointTypes, string(endpointType))
+				}
+				matchingResource.AddAttribute("EndpointType", strings.Join(endpointTypes, ","))
+			}
+
+			stageNames, err := apigwp.GetRestAPIStages(matchingResource.Id)
+			if err != nil {
+				return matchingResources, err
+			}
+			apigwp.addAPIDetails(&matchingResource, restAPIEndpoint, stageNames)
+
+			for _, ipAddr := range restAPIIPAddrs {
+				matchingResource.AddPublicIPAddr(ipAddr)
+			}
+
+			log.Debug("IP ", tgt, " found as API Gateway REST API -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	for _, v2API := range v2APIs {
+		if aws.ToBool(v2API.DisableExecuteApiEndpoint) {
+			continue
+		}
+
+		apiEndpoint := GetAPIEndpointHost(aws.ToString(v2API.ApiEndpoint))
+		apiIPAddrs := resolveDNSName(apiEndpoint)
+
+		for _, tgt := range ipMatcher.MatchAny(apiIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(v2API.ApiId)
+			matchingResource.RID = BuildAPIGatewayARN(region, "/apis/"+matchingResource.Id)
+			matchingResource.Name = aws.ToString(v2API.Name)
+			matchingResource.CloudSvc = "apigateway"
+			matchingResource.Tags = v2API.Tags
+
+			matchingResource.AddAttribute("ProtocolType", string(v2API.ProtocolType))
+			matchingResource.AddAttribute("EndpointType", string(v2types.EndpointTypeRegional))
+
+			stageNames, err := apigwp.GetV2APIStages(matchingResource.Id)
+			if err != nil {
+				return matchingResources, err
+			}
+			apigwp.addAPIDetails(&matchingResource, apiEndpoint, stageNames)
+
+			for _, ipAddr := range apiIPAddrs {
+				matchingResource.AddPublicIPAddr(ipAddr)
+			}
+
+			log.Debug("IP ", tgt, " found as API Gateway ", v2API.ProtocolType, " API -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	for _, domainName := range domainNames {
+		for _, domainNameConfig := range domainName.DomainNameConfigurations {
+			// regional domains point to a regional API Gateway endpoint, while edge-optimized ones point to an AWS-managed CloudFront distribution
+			targetDomainName := aws.ToString(domainNameConfig.ApiGatewayDomainName)
+			domainIPAddrs := resolveDNSName(targetDomainName)
+
+			for _, tgt := range ipMatcher.MatchAny(domainIPAddrs) {
+				var matchingResource generalResource.Resource
+
+				matchingResource.Id = aws.ToString(domainName.DomainName)
+				matchingResource.RID = aws.ToString(domainName.DomainNameArn)
+				if matchingResource.RID == "" {
+					matchingResource.RID = BuildAPIGatewayARN(region, "/domainnames/"+matchingResource.Id)
+				}
+				matchingResource.Name = matchingResource.Id
+				matchingResource.CloudSvc = "apigateway"
+				matchingResource.Status = string(domainNameConfig.DomainNameStatus)
+				matchingResource.Tags = domainName.Tags
+
+				apiMappings, err := apigwp.GetAPIMappings(matchingResource.Id)
+				if err != nil {
+					return matchingResources, err
+				}
+				AddCustomDomainDetails(&matchingResource, domainNameConfig, apiMappings)
+
+				for _, ipAddr := range domainIPAddrs {
+					matchingResource.AddPublicIPAddr(ipAddr)
+				}
+
+				if apigwp.NetworkMapping {
+					var formattedMappings []string
+					for _, apiMapping := range apiMappings {
+						formattedMappings = append(formattedMappings, FormatAPIMapping(apiMapping))
+					}
+
+					matchingResource.NetworkMap = append(matchingResource.NetworkMap, matchingResource.Id, targetDomainName, utils.FormatStrSliceAsCSV(formattedMappings))
+				}
+
+				log.Debug("IP ", tgt, " found as API Gateway custom domain -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+				matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+			}
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (apigwp APIGatewayPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(apigwp, tgtIP)
+}
@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func (apigwp APIGatewayPlugin) GetDomainNames() ([]types.DomainName, error) {
+	// custom domain names are shared between v1 and v2 APIs, but only the v2 API returns the mappings for every type of API
+	var domainNames []types.DomainName
+	var nextToken *string
+
+	apigwClient := apigatewayv2.NewFromConfig(apigwp.AwsConn.AwsConfig)
+
+	for {
+		output, err := apigwClient.GetDomainNames(context.TODO(), &apigatewayv2.GetDomainNamesInput{NextToken: nextToken})
+		if err != nil {
+			return domainNames, err
+		}
+
+		domainNames = append(domainNames, output.Items...)
+
+		nextToken = output.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return domainNames, nil
+}
+
+func (apigwp APIGatewayPlugin) GetAPIMappings(domainName string) ([]types.ApiMapping, error) {
+	var apiMappings []types.ApiMapping
+	var nextToken *string
+
+	apigwClient := apigatewayv2.NewFromConfig(apigwp.AwsConn.AwsConfig)
+
+	for {
+		output, err := apigwClient.GetApiMappings(context.TODO(), &apigatewayv2.GetApiMappingsInput{DomainName: &domainName, NextToken: nextToken})
+		if err != nil {
+			return apiMappings, err
+		}
+
+		apiMappings = append(apiMappings, output.Items...)
+
+		nextToken = output.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return apiMappings, nil
+}
+
+// FormatAPIMapping describes where a path on the custom domain is routed to, e.g. /orders -> a1b2c3d4e5/prod
+func FormatAPIMapping(apiMapping types.ApiMapping) string {
+	return fmt.Sprintf("/%s -> %s/%s", aws.ToString(apiMapping.ApiMappingKey), aws.ToString(apiMapping.ApiId), aws.ToString(apiMapping.Stage))
+}
+
+func AddCustomDomainDetails(matchingResource *generalResource.Resource, domainNameConfig types.DomainNameConfiguration, apiMappings []types.ApiMapping) {
+	var apiIDs, formattedMappings []string
+	for _, apiMapping := range apiMappings {
+		// the same API can be mapped to several paths, e.g. one per stage
+		if apiID := aws.ToString(apiMapping.ApiId); !slices.Contains(apiIDs, apiID) {
+			apiIDs = append(apiIDs, apiID)
+		}
+		formattedMappings = append(formattedMappings, FormatAPIMapping(apiMapping))
+	}
+
+	matchingResource.AddAttribute("ProtocolType", "CustomDomain")
+	matchingResource.AddAttribute("EndpointType", string(domainNameConfig.EndpointType))
+	matchingResource.AddAttribute("ApiGatewayDomainName", aws.ToString(domainNameConfig.ApiGatewayDomainName))
+	matchingResource.AddAttribute("ApiIds", strings.Join(apiIDs, ","))
+	matchingResource.AddAttribute("ApiMappings", strings.Join(formattedMappings, ","))
+}
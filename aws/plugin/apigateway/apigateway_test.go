@@ -0,0 +1,158 @@
+package plugin_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	v2types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/apigateway"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func apigwpFactory() plugin.APIGatewayPlugin {
+	ac, _ := awsconnector.New()
+
+	apigwp := plugin.APIGatewayPlugin{AwsConn: ac}
+
+	return apigwp
+}
+
+func TestGetResources(t *testing.T) {
+	apigwp := apigwpFactory()
+
+	apigwResources, _ := apigwp.GetResources()
+
+	expectedType := "RestApi"
+	for _, restAPI := range apigwResources {
+		restAPIType := reflect.TypeOf(restAPI)
+		if restAPIType.Name() != expectedType {
+			t.Errorf("Fetching resources via API Gateway Plugin failed; wanted %s type, received %s", expectedType, restAPIType.Name())
+		}
+	}
+}
+
+func TestBuildAPIGatewayARN(t *testing.T) {
+	var tests = []struct {
+		region, resourcePath, expectedARN string
+	}{
+		{"us-east-1", "/restapis/a1b2c3d4e5", "arn:aws:apigateway:us-east-1::/restapis/a1b2c3d4e5"},
+		{"cn-north-1", "/apis/a1b2c3d4e5", "arn:aws-cn:apigateway:cn-north-1::/apis/a1b2c3d4e5"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.region, func(t *testing.T) {
+			if apigwARN := plugin.BuildAPIGatewayARN(td.region, td.resourcePath); apigwARN != td.expectedARN {
+				t.Errorf("Building API Gateway ARN failed; expected %s, received %s", td.expectedARN, apigwARN)
+			}
+		})
+	}
+}
+
+func TestGetRestAPIEndpoint(t *testing.T) {
+	var tests = []struct {
+		region, expectedEndpoint string
+	}{
+		{"us-east-1", "a1b2c3d4e5.execute-api.us-east-1.amazonaws.com"},
+		{"cn-north-1", "a1b2c3d4e5.execute-api.cn-north-1.amazonaws.com.cn"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.region, func(t *testing.T) {
+			if endpoint := plugin.GetRestAPIEndpoint("a1b2c3d4e5", td.region); endpoint != td.expectedEndpoint {
+				t.Errorf("Building REST API endpoint failed; expected %s, received %s", td.expectedEndpoint, endpoint)
+			}
+		})
+	}
+}
+
+func TestGetAPIEndpointHost(t *testing.T) {
+	var tests = []struct {
+		apiEndpoint, expectedHost string
+	}{
+		{"https://a1b2c3d4e5.execute-api.us-east-1.amazonaws.com", "a1b2c3d4e5.execute-api.us-east-1.amazonaws.com"},
+		{"wss://a1b2c3d4e5.execute-api.us-east-1.amazonaws.com", "a1b2c3d4e5.execute-api.us-east-1.amazonaws.com"},
+		{"", ""},
+	}
+
+	for _, td := range tests {
+		t.Run(td.apiEndpoint, func(t *testing.T) {
+			if host := plugin.GetAPIEndpointHost(td.apiEndpoint); host != td.expectedHost {
+				t.Errorf("Parsing API endpoint failed; expected %s, received %s", td.expectedHost, host)
+			}
+		})
+	}
+}
+
+func TestIsPrivateRestAPI(t *testing.T) {
+	var tests = []struct {
+		name     string
+		restAPI  types.RestApi
+		expected bool
+	}{
+		{"regional", types.RestApi{EndpointConfiguration: &types.EndpointConfiguration{Types: []types.EndpointType{types.EndpointTypeRegional}}}, false},
+		{"private", types.RestApi{EndpointConfiguration: &types.EndpointConfiguration{Types: []types.EndpointType{types.EndpointTypePrivate}}}, true},
+		{"noEndpointConfig", types.RestApi{}, false},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			if isPrivate := plugin.IsPrivateRestAPI(td.restAPI); isPrivate != td.expected {
+				t.Errorf("Checking for private REST API failed; expected %t, received %t", td.expected, isPrivate)
+			}
+		})
+	}
+}
+
+func TestAddCustomDomainDetails(t *testing.T) {
+	var matchingResource generalResource.Resource
+
+	plugin.AddCustomDomainDetails(
+		&matchingResource,
+		v2types.DomainNameConfiguration{
+			ApiGatewayDomainName: aws.String("d-0123456789.execute-api.us-east-1.amazonaws.com"),
+			EndpointType:         v2types.EndpointTypeRegional,
+		},
+		[]v2types.ApiMapping{
+			{ApiId: aws.String("a1b2c3d4e5"), ApiMappingKey: aws.String(""), Stage: aws.String("prod")},
+			{ApiId: aws.String("a1b2c3d4e5"), ApiMappingKey: aws.String("v2"), Stage: aws.String("beta")},
+			{ApiId: aws.String("f6g7h8i9j0"), ApiMappingKey: aws.String("orders"), Stage: aws.String("$default")},
+		},
+	)
+
+	expectedAttributes := map[string]string{
+		"ProtocolType":         "CustomDomain",
+		"EndpointType":         "REGIONAL",
+		"ApiGatewayDomainName": "d-0123456789.execute-api.us-east-1.amazonaws.com",
+		"ApiIds":               "a1b2c3d4e5,f6g7h8i9j0",
+		"ApiMappings":          "/ -> a1b2c3d4e5/prod,/v2 -> a1b2c3d4e5/beta,/orders -> f6g7h8i9j0/$default",
+	}
+	if !reflect.DeepEqual(matchingResource.Attributes, expectedAttributes) {
+		t.Errorf("Adding custom domain details failed; expected %v, received %v", expectedAttributes, matchingResource.Attributes)
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	apigwp := apigwpFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.ipAddr, func(t *testing.T) {
+			matchedAPI, _ := apigwp.SearchResources(td.ipAddr)
+			matchedAPIType := reflect.TypeOf(matchedAPI)
+
+			if matchedAPIType.Name() != td.expectedType {
+				t.Errorf("API Gateway search failed; expected %s after search, received %s", td.expectedType, matchedAPIType.Name())
+			}
+		})
+	}
+}
@@ -3,14 +3,17 @@ package plugin
 import (
 	"context"
 	"net"
+	"strconv"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
 	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
@@ -20,6 +23,15 @@ type CloudfrontPlugin struct {
 	NetworkMapping bool
 }
 
+func init() {
+	// CloudFront distributions aren't tied to a region, so there's no need to search them in every region
+	registry.Register("aws", "cloudfront", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return CloudfrontPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping}
+	}, registry.Global)
+}
+
 func processCloudfrontOrigins(originSet []types.Origin) []CloudfrontOrigin {
 	var origins []CloudfrontOrigin
 
@@ -40,6 +52,17 @@ func NormalizeCFDistroFQDN(fqdn string) string {
 	return strings.TrimSuffix(fqdn, ".")
 }
 
+func AddCFDistroDetails(matchingResource *generalResource.Resource, cfDistro types.DistributionSummary) {
+	matchingResource.Id = aws.ToString(cfDistro.Id)
+	matchingResource.Name = NormalizeCFDistroFQDN(aws.ToString(cfDistro.DomainName))
+	matchingResource.Status = aws.ToString(cfDistro.Status)
+
+	if cfDistro.Aliases != nil {
+		matchingResource.AddAttribute("Aliases", strings.Join(cfDistro.Aliases.Items, ","))
+	}
+	matchingResource.AddAttribute("Enabled", strconv.FormatBool(aws.ToBool(cfDistro.Enabled)))
+}
+
 func (cfp CloudfrontPlugin) GetResources() ([]types.DistributionSummary, error) {
 	var distros []types.DistributionSummary
 
@@ -58,49 +81,59 @@ func (cfp CloudfrontPlugin) GetResources() ([]types.DistributionSummary, error)
 	return distros, nil
 }
 
-func (cfp CloudfrontPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+func (cfp CloudfrontPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	// CloudFront edge IPs are shared by many distributions, so a single IP can map to several of them
 	var cfDistroFQDN string
 	var cfIPAddrs []net.IP
-	var cfDistroOriginSet []CloudfrontOrigin
-	var matchingResource generalResource.Resource
-	var originIdSet, originDomainNameSet []string
+	matchingResources := map[string][]generalResource.Resource{}
 
 	cfResources, err := cfp.GetResources()
 	if err != nil {
-		return matchingResource, err
+		return matchingResources, err
 	}
 
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
 	for _, cfDistro := range cfResources {
 		cfDistroFQDN = NormalizeCFDistroFQDN(*cfDistro.DomainName)
 		cfIPAddrs, err = utils.LookupFQDN(cfDistroFQDN)
 		if err != nil {
-			return matchingResource, err
+			return matchingResources, err
 		}
+		cfIPAddrStrs := utils.FormatIPAddrs(cfIPAddrs)
 
-		cfDistroOriginSet = processCloudfrontOrigins(cfDistro.Origins.Items)
+		for _, tgt := range ipMatcher.MatchAny(cfIPAddrStrs) {
+			var matchingResource generalResource.Resource
 
-		for _, ipAddr := range cfIPAddrs {
-			if ipAddr.String() == tgtIP {
-				matchingResource.RID = *cfDistro.ARN
-				matchingResource.CloudSvc = "cloudfront"
+			matchingResource.RID = *cfDistro.ARN
+			matchingResource.CloudSvc = "cloudfront"
+			AddCFDistroDetails(&matchingResource, cfDistro)
 
-				if cfp.NetworkMapping {
-					matchingResource.NetworkMap = append(matchingResource.NetworkMap, *cfDistro.DomainName, *cfDistro.Id)
+			for _, ipAddr := range cfIPAddrStrs {
+				matchingResource.AddPublicIPAddr(ipAddr)
+			}
 
-					for _, normalizedOrigin := range cfDistroOriginSet {
-						originIdSet = append(originIdSet, normalizedOrigin.OriginId)
-						originDomainNameSet = append(originDomainNameSet, normalizedOrigin.DomainName)
-					}
-					matchingResource.NetworkMap = append(matchingResource.NetworkMap, utils.FormatStrSliceAsCSV(originIdSet))
-					matchingResource.NetworkMap = append(matchingResource.NetworkMap, utils.FormatStrSliceAsCSV(originDomainNameSet))
-				}
+			if cfp.NetworkMapping {
+				var originIdSet, originDomainNameSet []string
 
-				log.Debug("IP found as CloudFront distribution -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, *cfDistro.DomainName, *cfDistro.Id)
 
-				break
+				for _, normalizedOrigin := range processCloudfrontOrigins(cfDistro.Origins.Items) {
+					originIdSet = append(originIdSet, normalizedOrigin.OriginId)
+					originDomainNameSet = append(originDomainNameSet, normalizedOrigin.DomainName)
+				}
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, utils.FormatStrSliceAsCSV(originIdSet))
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, utils.FormatStrSliceAsCSV(originDomainNameSet))
 			}
+
+			log.Debug("IP ", tgt, " found as CloudFront distribution -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
 		}
 	}
 
-	return matchingResource, nil
+	return matchingResources, nil
+}
+
+func (cfp CloudfrontPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(cfp, tgtIP)
 }
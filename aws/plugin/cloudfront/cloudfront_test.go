@@ -4,8 +4,12 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
 	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/cloudfront"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 func cfpFactory() plugin.CloudfrontPlugin {
@@ -78,3 +82,25 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestAddCFDistroDetails(t *testing.T) {
+	var matchingResource generalResource.Resource
+
+	plugin.AddCFDistroDetails(&matchingResource, types.DistributionSummary{
+		Id:         aws.String("E1234567890ABC"),
+		DomainName: aws.String("d111111abcdef8.cloudfront.net."),
+		Status:     aws.String("Deployed"),
+		Enabled:    aws.Bool(true),
+		Aliases:    &types.Aliases{Items: []string{"www.example.com", "example.com"}},
+	})
+
+	expectedResource := generalResource.Resource{
+		Id:         "E1234567890ABC",
+		Name:       "d111111abcdef8.cloudfront.net",
+		Status:     "Deployed",
+		Attributes: map[string]string{"Aliases": "www.example.com,example.com", "Enabled": "true"},
+	}
+	if !reflect.DeepEqual(matchingResource, expectedResource) {
+		t.Errorf("Adding CloudFront distribution details failed; expected %+v, received %+v", expectedResource, matchingResource)
+	}
+}
@@ -0,0 +1,432 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	ec2p "github.com/magneticstain/ip-2-cloudresource/aws/plugin/ec2"
+	eksp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eks"
+	enip "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eni"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// advanced query expressions are limited to 4096 characters, so targets are queried in batches
+const maxQueryTgts = 8
+
+const (
+	eniResourceType = "AWS::EC2::NetworkInterface"
+	eipResourceType = "AWS::EC2::EIP"
+)
+
+// configuration fields that hold the IPv4 addresses of each resource type, in the order they're queried; Config indexes these as IP addresses, so they can also be matched against a CIDR block
+var (
+	publicIPv4Fields = []string{
+		"configuration.association.publicIp",
+		"configuration.privateIpAddresses.association.publicIp",
+		"configuration.publicIp",
+	}
+	privateIPv4Fields = []string{
+		"configuration.privateIpAddress",
+		"configuration.privateIpAddresses.privateIpAddress",
+	}
+)
+
+// IPv6 addresses aren't documented as an IP address field, so they're only matched exactly
+const ipv6Field = "configuration.ipv6Addresses.ipv6Address"
+
+// IDs are embedded in query expressions, so they're validated before use
+var queryIDPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// ConfigPlugin searches the resources recorded by an AWS Config aggregator, which answers org-wide lookups without assuming a role in each account; it isn't registered as a service plugin since it searches across services
+type ConfigPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	AggregatorName string
+	NetworkMapping bool
+	PrivateSearch  bool
+	VpcID          string
+	// limits the search to these regions, if set
+	Regions []string
+}
+
+type ConfigItemTag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ConfigItem is a single result of an advanced query; the configuration is left raw since its shape depends on the resource type
+type ConfigItem struct {
+	ResourceID    string          `json:"resourceId"`
+	ResourceName  string          `json:"resourceName"`
+	ResourceType  string          `json:"resourceType"`
+	AccountID     string          `json:"accountId"`
+	AwsRegion     string          `json:"awsRegion"`
+	Arn           string          `json:"arn"`
+	Configuration json.RawMessage `json:"configuration"`
+	Tags          []ConfigItemTag `json:"tags"`
+}
+
+type configIPAssociation struct {
+	PublicIP string `json:"publicIp"`
+}
+
+// configNetworkInterface maps the fields of a network interface's configuration that are needed to resolve its owner and IPs; Config records the same model as the EC2 API, but only these fields are parsed so that the rest (e.g. timestamps) can't break parsing
+type configNetworkInterface struct {
+	NetworkInterfaceID string               `json:"networkInterfaceId"`
+	Description        string               `json:"description"`
+	InterfaceType      string               `json:"interfaceType"`
+	OwnerID            string               `json:"ownerId"`
+	RequesterID        string               `json:"requesterId"`
+	RequesterManaged   bool                 `json:"requesterManaged"`
+	Status             string               `json:"status"`
+	VpcID              string               `json:"vpcId"`
+	SubnetID           string               `json:"subnetId"`
+	Association        *configIPAssociation `json:"association"`
+	PrivateIPAddresses []struct {
+		PrivateIPAddress string               `json:"privateIpAddress"`
+		Association      *configIPAssociation `json:"association"`
+	} `json:"privateIpAddresses"`
+	Ipv6Addresses []struct {
+		Ipv6Address string `json:"ipv6Address"`
+	} `json:"ipv6Addresses"`
+	Attachment *struct {
+		InstanceID string `json:"instanceId"`
+	} `json:"attachment"`
+}
+
+// configAddress maps the fields of an elastic IP's configuration that are reported
+type configAddress struct {
+	AllocationID   string `json:"allocationId"`
+	AssociationID  string `json:"associationId"`
+	PublicIP       string `json:"publicIp"`
+	PublicIpv4Pool string `json:"publicIpv4Pool"`
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+func (configAssociation *configIPAssociation) toAssociation() *types.NetworkInterfaceAssociation {
+	if configAssociation == nil {
+		return nil
+	}
+
+	return &types.NetworkInterfaceAssociation{PublicIp: optionalString(configAssociation.PublicIP)}
+}
+
+// toNetworkInterface converts the configuration into the EC2 API's model so that it can be handled the same way as the eni service's results
+func (configENI configNetworkInterface) toNetworkInterface() types.NetworkInterface {
+	eni := types.NetworkInterface{
+		NetworkInterfaceId: optionalString(configENI.NetworkInterfaceID),
+		Description:        optionalString(configENI.Description),
+		InterfaceType:      types.NetworkInterfaceType(configENI.InterfaceType),
+		OwnerId:            optionalString(configENI.OwnerID),
+		RequesterId:        optionalString(configENI.RequesterID),
+		RequesterManaged:   aws.Bool(configENI.RequesterManaged),
+		Status:             types.NetworkInterfaceStatus(configENI.Status),
+		VpcId:              optionalString(configENI.VpcID),
+		SubnetId:           optionalString(configENI.SubnetID),
+		Association:        configENI.Association.toAssociation(),
+	}
+
+	for _, privateIPAddr := range configENI.PrivateIPAddresses {
+		eni.PrivateIpAddresses = append(eni.PrivateIpAddresses, types.NetworkInterfacePrivateIpAddress{
+			PrivateIpAddress: optionalString(privateIPAddr.PrivateIPAddress),
+			Association:      privateIPAddr.Association.toAssociation(),
+		})
+	}
+
+	for _, ipv6Addr := range configENI.Ipv6Addresses {
+		eni.Ipv6Addresses = append(eni.Ipv6Addresses, types.NetworkInterfaceIpv6Address{Ipv6Address: optionalString(ipv6Addr.Ipv6Address)})
+	}
+
+	if configENI.Attachment != nil {
+		eni.Attachment = &types.NetworkInterfaceAttachment{InstanceId: optionalString(configENI.Attachment.InstanceID)}
+	}
+
+	return eni
+}
+
+func (tag ConfigItemTag) KeyValue() (string, string) {
+	return tag.Key, tag.Value
+}
+
+func formatQueryValues(values []string) string {
+	quotedValues := make([]string, 0, len(values))
+	for _, value := range values {
+		quotedValues = append(quotedValues, "'"+value+"'")
+	}
+
+	return strings.Join(quotedValues, ", ")
+}
+
+// formatIPConditions generates the conditions that match the target against each IP field; IPv4 CIDR blocks are matched natively by Config's IP address fields, e.g. configuration.privateIpAddress = '10.0.0.0/24'
+func formatIPConditions(tgt string, ipv4Fields []string) ([]string, error) {
+	var ipConditions []string
+
+	if strings.Contains(tgt, "/") {
+		prefix, err := netip.ParsePrefix(tgt)
+		if err != nil {
+			return nil, nil
+		}
+		if !prefix.Addr().Is4() {
+			return nil, fmt.Errorf("IPv6 CIDR blocks can't be searched for via AWS Config: %s", tgt)
+		}
+
+		for _, ipField := range ipv4Fields {
+			ipConditions = append(ipConditions, fmt.Sprintf("%s = '%s'", ipField, prefix.Masked()))
+		}
+
+		return ipConditions, nil
+	}
+
+	ipAddr, err := netip.ParseAddr(tgt)
+	if err != nil {
+		return nil, nil
+	}
+	ipAddr = ipAddr.Unmap()
+
+	if ipAddr.Is6() {
+		return []string{fmt.Sprintf("%s = '%s'", ipv6Field, ipAddr)}, nil
+	}
+
+	for _, ipField := range ipv4Fields {
+		ipConditions = append(ipConditions, fmt.Sprintf("%s = '%s'", ipField, ipAddr))
+	}
+
+	return ipConditions, nil
+}
+
+// BuildQuery generates the advanced query that finds every resource using any of the target IPs; invalid targets are skipped, while IPv6 CIDR blocks are rejected since IPv6 addresses can only be matched exactly
+func BuildQuery(tgtIPs []string, privateSearch bool, vpcID string, regions []string) (string, error) {
+	ipv4Fields := publicIPv4Fields
+	resourceTypes := []string{eniResourceType, eipResourceType}
+	if privateSearch {
+		ipv4Fields = privateIPv4Fields
+		resourceTypes = []string{eniResourceType}
+	}
+
+	var ipConditions []string
+	for _, tgt := range tgtIPs {
+		tgtConditions, err := formatIPConditions(tgt, ipv4Fields)
+		if err != nil {
+			return "", err
+		}
+
+		ipConditions = append(ipConditions, tgtConditions...)
+	}
+	if len(ipConditions) == 0 {
+		return "", fmt.Errorf("no valid IPs to query for in %v", tgtIPs)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT resourceId, resourceName, resourceType, accountId, awsRegion, arn, configuration, tags WHERE resourceType IN (%s) AND (%s)",
+		formatQueryValues(resourceTypes),
+		strings.Join(ipConditions, " OR "),
+	)
+
+	if vpcID != "" {
+		if !queryIDPattern.MatchString(vpcID) {
+			return "", fmt.Errorf("invalid VPC ID: %s", vpcID)
+		}
+
+		query += fmt.Sprintf(" AND configuration.vpcId = '%s'", vpcID)
+	}
+
+	if len(regions) > 0 {
+		for _, region := range regions {
+			if !queryIDPattern.MatchString(region) {
+				return "", fmt.Errorf("invalid region: %s", region)
+			}
+		}
+
+		query += fmt.Sprintf(" AND awsRegion IN (%s)", formatQueryValues(regions))
+	}
+
+	return query, nil
+}
+
+func (configp ConfigPlugin) GetResources(query string) ([]ConfigItem, error) {
+	var configItems []ConfigItem
+
+	configClient := configservice.NewFromConfig(configp.AwsConn.AwsConfig)
+	paginator := configservice.NewSelectAggregateResourceConfigPaginator(configClient, &configservice.SelectAggregateResourceConfigInput{
+		ConfigurationAggregatorName: &configp.AggregatorName,
+		Expression:                  &query,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return configItems, err
+		}
+
+		// each result is a JSON document containing the selected fields
+		for _, result := range output.Results {
+			var configItem ConfigItem
+			err = json.Unmarshal([]byte(result), &configItem)
+			if err != nil {
+				return configItems, fmt.Errorf("unable to parse query result: %w", err)
+			}
+
+			configItems = append(configItems, configItem)
+		}
+	}
+
+	return configItems, nil
+}
+
+// ConvertENIItem builds the resource that owns a network interface recorded by Config, along with the IPs of the interface
+func (configp ConfigPlugin) ConvertENIItem(configItem ConfigItem) (generalResource.Resource, []string, error) {
+	var matchingResource generalResource.Resource
+	var configENI configNetworkInterface
+
+	err := json.Unmarshal(configItem.Configuration, &configENI)
+	if err != nil {
+		return matchingResource, nil, err
+	}
+
+	eni := configENI.toNetworkInterface()
+	if eni.OwnerId == nil {
+		eni.OwnerId = &configItem.AccountID
+	}
+
+	eniIPAddrs := enip.GetPublicIPAddrs(eni)
+	if configp.PrivateSearch {
+		eniIPAddrs = enip.GetPrivateIPAddrs(eni)
+	}
+
+	eniOwner := enip.ResolveENIOwner(eni, configItem.AwsRegion)
+	matchingResource.Id = configItem.ResourceID
+	matchingResource.RID = eniOwner.RID
+	matchingResource.CloudSvc = eniOwner.CloudSvc
+	matchingResource.AccountID = configItem.AccountID
+	matchingResource.Region = configItem.AwsRegion
+	matchingResource.Status = string(eni.Status)
+	matchingResource.Tags = utils.FormatTags(configItem.Tags, ConfigItemTag.KeyValue)
+	matchingResource.AddAttribute("Source", "config")
+	matchingResource.AddAttribute("InterfaceType", string(eni.InterfaceType))
+	matchingResource.AddAttribute("Description", aws.ToString(eni.Description))
+	matchingResource.AddAttribute("VpcId", aws.ToString(eni.VpcId))
+
+	k8sOwner := eksp.ParseKubernetesTags(matchingResource.Tags)
+	if k8sOwner.Cluster == "" {
+		k8sOwner.Cluster = eksp.ParseEKSENIDescription(aws.ToString(eni.Description))
+	}
+	eksp.AddKubernetesDetails(&matchingResource, k8sOwner)
+
+	for _, ipAddr := range eniIPAddrs {
+		matchingResource.AddIPAddr(ipAddr, configp.PrivateSearch)
+	}
+
+	if configp.NetworkMapping {
+		matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(eni.VpcId), aws.ToString(eni.SubnetId), configItem.ResourceID)
+	}
+
+	return matchingResource, eniIPAddrs, nil
+}
+
+// ConvertEIPItem builds the resource for an elastic IP recorded by Config; associated EIPs are skipped since they're reported via the network interface they're associated with
+func (configp ConfigPlugin) ConvertEIPItem(configItem ConfigItem) (generalResource.Resource, []string, error) {
+	var matchingResource generalResource.Resource
+	var eip configAddress
+
+	err := json.Unmarshal(configItem.Configuration, &eip)
+	if err != nil {
+		return matchingResource, nil, err
+	}
+
+	if eip.AssociationID != "" {
+		return matchingResource, nil, nil
+	}
+
+	eipIPAddr := eip.PublicIP
+
+	matchingResource.Id = eip.AllocationID
+	if matchingResource.Id == "" {
+		matchingResource.Id = configItem.ResourceID
+	}
+	matchingResource.RID = matchingResource.Id
+	matchingResource.CloudSvc = "eip"
+	matchingResource.AccountID = configItem.AccountID
+	matchingResource.Region = configItem.AwsRegion
+	matchingResource.Status = ec2p.EIPUnassociatedStatus
+	matchingResource.Tags = utils.FormatTags(configItem.Tags, ConfigItemTag.KeyValue)
+	matchingResource.Name = matchingResource.Tags["Name"]
+	matchingResource.AddPublicIPAddr(eipIPAddr)
+
+	matchingResource.AddAttribute("Source", "config")
+	matchingResource.AddAttribute("PublicIpv4Pool", eip.PublicIpv4Pool)
+	matchingResource.AddAttribute("PublicIpv4PoolType", ec2p.GetIPv4PoolType(eip.PublicIpv4Pool))
+
+	return matchingResource, []string{eipIPAddr}, nil
+}
+
+func (configp ConfigPlugin) convertItem(configItem ConfigItem) (generalResource.Resource, []string, error) {
+	switch configItem.ResourceType {
+	case eniResourceType:
+		return configp.ConvertENIItem(configItem)
+	case eipResourceType:
+		return configp.ConvertEIPItem(configItem)
+	default:
+		return generalResource.Resource{}, nil, fmt.Errorf("unsupported resource type: %s", configItem.ResourceType)
+	}
+}
+
+func (configp ConfigPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	for tgtChunk := range slices.Chunk(tgtIPs, maxQueryTgts) {
+		query, err := BuildQuery(tgtChunk, configp.PrivateSearch, configp.VpcID, configp.Regions)
+		if err != nil {
+			return matchingResources, err
+		}
+
+		log.Debug("running AWS Config advanced query against aggregator ", configp.AggregatorName, ": ", query)
+
+		configItems, err := configp.GetResources(query)
+		if err != nil {
+			return matchingResources, err
+		}
+
+		// only match against the targets in this batch, since other batches will return the same resources if they match those
+		ipMatcher := utils.NewIPMatcher(tgtChunk)
+
+		for _, configItem := range configItems {
+			matchingResource, ipAddrs, err := configp.convertItem(configItem)
+			if err != nil {
+				// one malformed configuration item shouldn't sink the rest of the results
+				log.Warn("unable to parse configuration of ", configItem.ResourceID, " returned by AWS Config: ", err)
+				continue
+			}
+
+			for _, tgt := range ipMatcher.MatchAny(ipAddrs) {
+				log.Debug("IP ", tgt, " found in AWS Config as ", matchingResource.CloudSvc, " resource -> ", matchingResource.RID, " in ", matchingResource.AccountID, " (", matchingResource.Region, ")")
+				matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+			}
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (configp ConfigPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(configp, tgtIP)
+}
@@ -0,0 +1,292 @@
+package plugin_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/config"
+)
+
+func TestBuildQuery(t *testing.T) {
+	var tests = []struct {
+		testName                 string
+		tgtIPs                   []string
+		privateSearch            bool
+		vpcID                    string
+		regions, expectedClauses []string
+		expectErr                bool
+	}{
+		{
+			"public",
+			[]string{"203.0.113.10"},
+			false, "", nil,
+			[]string{"resourceType IN ('AWS::EC2::NetworkInterface', 'AWS::EC2::EIP')", "configuration.association.publicIp = '203.0.113.10'", "configuration.publicIp = '203.0.113.10'"},
+			false,
+		},
+		{
+			"ipv6",
+			[]string{"2600:1f18:243e:1300::10"},
+			false, "", nil,
+			[]string{"AND (configuration.ipv6Addresses.ipv6Address = '2600:1f18:243e:1300::10')"},
+			false,
+		},
+		{
+			"private",
+			[]string{"10.0.1.5"},
+			true, "vpc-0123456789abcdef0", []string{"us-east-1", "eu-west-1"},
+			[]string{"resourceType IN ('AWS::EC2::NetworkInterface')", "configuration.privateIpAddresses.privateIpAddress = '10.0.1.5'", "AND configuration.vpcId = 'vpc-0123456789abcdef0'", "AND awsRegion IN ('us-east-1', 'eu-west-1')"},
+			false,
+		},
+		{"invalidIPs", []string{"not-an-ip"}, false, "", nil, nil, true},
+		{"ipv6CIDR", []string{"203.0.113.10", "2600:1f18:243e:1300::/56"}, false, "", nil, nil, true},
+		{"invalidVPC", []string{"10.0.1.5"}, true, "vpc-0' OR '1'='1", nil, nil, true},
+		{"invalidRegion", []string{"203.0.113.10"}, false, "", []string{"us-east-1'"}, nil, true},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			query, err := plugin.BuildQuery(td.tgtIPs, td.privateSearch, td.vpcID, td.regions)
+			if td.expectErr {
+				if err == nil {
+					t.Errorf("Building AWS Config query should have failed, but returned: %s", query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Building AWS Config query failed: %s", err)
+			}
+
+			for _, clause := range td.expectedClauses {
+				if !strings.Contains(query, clause) {
+					t.Errorf("Building AWS Config query failed; expected clause %q in %s", clause, query)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildQuery_CIDR(t *testing.T) {
+	var tests = []struct {
+		testName      string
+		privateSearch bool
+		expectedQuery string
+	}{
+		{
+			"public",
+			false,
+			"SELECT resourceId, resourceName, resourceType, accountId, awsRegion, arn, configuration, tags WHERE resourceType IN ('AWS::EC2::NetworkInterface', 'AWS::EC2::EIP') AND (" +
+				"configuration.association.publicIp = '203.0.113.0/24' OR configuration.privateIpAddresses.association.publicIp = '203.0.113.0/24' OR configuration.publicIp = '203.0.113.0/24')",
+		},
+		{
+			"private",
+			true,
+			"SELECT resourceId, resourceName, resourceType, accountId, awsRegion, arn, configuration, tags WHERE resourceType IN ('AWS::EC2::NetworkInterface') AND (" +
+				"configuration.privateIpAddress = '203.0.113.0/24' OR configuration.privateIpAddresses.privateIpAddress = '203.0.113.0/24')",
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			// the CIDR block is normalized, and only matched against the fields that Config indexes as IP addresses
+			query, err := plugin.BuildQuery([]string{"203.0.113.17/24"}, td.privateSearch, "", nil)
+			if err != nil {
+				t.Fatalf("Building AWS Config query for CIDR block failed: %s", err)
+			}
+
+			if query != td.expectedQuery {
+				t.Errorf("Building AWS Config query for CIDR block failed; expected %s, received %s", td.expectedQuery, query)
+			}
+		})
+	}
+}
+
+// results of an advanced query for a network interface used by an ALB, and an elastic IP, as returned by SelectAggregateResourceConfig
+const (
+	testENIResult = `{
+		"resourceId": "eni-0123456789abcdef0",
+		"resourceName": "",
+		"resourceType": "AWS::EC2::NetworkInterface",
+		"accountId": "123456789012",
+		"awsRegion": "us-east-1",
+		"arn": "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0123456789abcdef0",
+		"configuration": {
+			"association": {"ipOwnerId": "amazon", "publicDnsName": "ec2-203-0-113-10.compute-1.amazonaws.com", "publicIp": "203.0.113.10"},
+			"attachment": {"attachTime": "2024-01-02T03:04:05.000Z", "attachmentId": "ela-attach-0123456789abcdef0", "deleteOnTermination": false, "deviceIndex": 1, "instanceOwnerId": "amazon-elb", "status": "attached"},
+			"availabilityZone": "us-east-1a",
+			"description": "ELB app/my-alb/50dc6c495c0c9188",
+			"groups": [{"groupName": "my-alb-sg", "groupId": "sg-0123456789abcdef0"}],
+			"interfaceType": "interface",
+			"ipv6Addresses": [{"ipv6Address": "2600:1f18:243e:1300::10"}],
+			"macAddress": "0a:1b:2c:3d:4e:5f",
+			"networkInterfaceId": "eni-0123456789abcdef0",
+			"ownerId": "123456789012",
+			"privateDnsName": "ip-10-0-1-5.ec2.internal",
+			"privateIpAddress": "10.0.1.5",
+			"privateIpAddresses": [
+				{
+					"association": {"ipOwnerId": "amazon", "publicDnsName": "ec2-203-0-113-10.compute-1.amazonaws.com", "publicIp": "203.0.113.10"},
+					"primary": true,
+					"privateDnsName": "ip-10-0-1-5.ec2.internal",
+					"privateIpAddress": "10.0.1.5"
+				}
+			],
+			"requesterId": "amazon-elb",
+			"requesterManaged": true,
+			"sourceDestCheck": true,
+			"status": "in-use",
+			"subnetId": "subnet-0123456789abcdef0",
+			"tagSet": [],
+			"vpcId": "vpc-0123456789abcdef0"
+		},
+		"tags": [{"key": "team", "value": "platform"}]
+	}`
+	testEIPResult = `{
+		"resourceId": "eipalloc-0123456789abcdef0",
+		"resourceType": "AWS::EC2::EIP",
+		"accountId": "123456789012",
+		"awsRegion": "us-east-1",
+		"arn": "arn:aws:ec2:us-east-1:123456789012:eip-allocation/eipalloc-0123456789abcdef0",
+		"configuration": {
+			"instanceId": null,
+			"publicIp": "203.0.113.20",
+			"allocationId": "eipalloc-0123456789abcdef0",
+			"associationId": %s,
+			"domain": "vpc",
+			"networkInterfaceId": null,
+			"networkInterfaceOwnerId": null,
+			"privateIpAddress": null,
+			"tags": [{"key": "Name", "value": "bastion"}],
+			"publicIpv4Pool": "amazon",
+			"networkBorderGroup": "us-east-1",
+			"customerOwnedIp": null,
+			"customerOwnedIpv4Pool": null,
+			"carrierIp": null
+		},
+		"tags": [{"key": "Name", "value": "bastion"}]
+	}`
+)
+
+func parseConfigItem(t *testing.T, result string) plugin.ConfigItem {
+	var configItem plugin.ConfigItem
+
+	if err := json.Unmarshal([]byte(result), &configItem); err != nil {
+		t.Fatalf("unable to parse AWS Config query result: %s", err)
+	}
+
+	return configItem
+}
+
+func TestConvertENIItem(t *testing.T) {
+	configItem := parseConfigItem(t, testENIResult)
+
+	var tests = []struct {
+		testName           string
+		configp            plugin.ConfigPlugin
+		expectedIPAddrs    []string
+		expectedNetworkMap []string
+	}{
+		{"public", plugin.ConfigPlugin{}, []string{"203.0.113.10", "2600:1f18:243e:1300::10"}, nil},
+		{"private", plugin.ConfigPlugin{PrivateSearch: true}, []string{"10.0.1.5", "2600:1f18:243e:1300::10"}, nil},
+		{"networkMapping", plugin.ConfigPlugin{NetworkMapping: true}, []string{"203.0.113.10", "2600:1f18:243e:1300::10"}, []string{"vpc-0123456789abcdef0", "subnet-0123456789abcdef0", "eni-0123456789abcdef0"}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			matchingResource, ipAddrs, err := td.configp.ConvertENIItem(configItem)
+			if err != nil {
+				t.Fatalf("Converting AWS Config network interface failed: %s", err)
+			}
+
+			if !slices.Equal(ipAddrs, td.expectedIPAddrs) {
+				t.Errorf("Converting AWS Config network interface failed; expected IPs %v, received %v", td.expectedIPAddrs, ipAddrs)
+			}
+
+			expectedRID := "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/50dc6c495c0c9188"
+			if matchingResource.CloudSvc != "elbv2" || matchingResource.RID != expectedRID || matchingResource.AccountID != "123456789012" || matchingResource.Region != "us-east-1" || matchingResource.Status != "in-use" {
+				t.Errorf("Converting AWS Config network interface failed; received %+v", matchingResource)
+			}
+
+			expectedAttributes := map[string]string{
+				"Source":        "config",
+				"InterfaceType": "interface",
+				"Description":   "ELB app/my-alb/50dc6c495c0c9188",
+				"VpcId":         "vpc-0123456789abcdef0",
+			}
+			if !maps.Equal(matchingResource.Attributes, expectedAttributes) || !maps.Equal(matchingResource.Tags, map[string]string{"team": "platform"}) {
+				t.Errorf("Converting AWS Config network interface failed; received attributes %v and tags %v", matchingResource.Attributes, matchingResource.Tags)
+			}
+
+			if !slices.Equal(matchingResource.NetworkMap, td.expectedNetworkMap) {
+				t.Errorf("Converting AWS Config network interface failed; expected network map %v, received %v", td.expectedNetworkMap, matchingResource.NetworkMap)
+			}
+		})
+	}
+}
+
+func TestConvertENIItem_Instance(t *testing.T) {
+	// the owner isn't part of this configuration, so the account of the configuration item is used to build the instance's ARN
+	configItem := parseConfigItem(t, `{
+		"resourceId": "eni-0123456789abcdef1",
+		"resourceType": "AWS::EC2::NetworkInterface",
+		"accountId": "123456789012",
+		"awsRegion": "us-west-2",
+		"configuration": {
+			"attachment": {"attachTime": "2024-01-02T03:04:05.000Z", "attachmentId": "eni-attach-0123456789abcdef0", "deleteOnTermination": true, "deviceIndex": 0, "instanceId": "i-0123456789abcdef0", "instanceOwnerId": "123456789012", "status": "attached"},
+			"description": "",
+			"interfaceType": "interface",
+			"networkInterfaceId": "eni-0123456789abcdef1",
+			"privateIpAddress": "10.0.2.7",
+			"privateIpAddresses": [{"primary": true, "privateIpAddress": "10.0.2.7"}],
+			"requesterManaged": false,
+			"status": "in-use",
+			"subnetId": "subnet-0123456789abcdef1",
+			"vpcId": "vpc-0123456789abcdef0"
+		},
+		"tags": []
+	}`)
+
+	matchingResource, ipAddrs, err := plugin.ConfigPlugin{PrivateSearch: true}.ConvertENIItem(configItem)
+	if err != nil {
+		t.Fatalf("Converting AWS Config network interface failed: %s", err)
+	}
+
+	expectedRID := "arn:aws:ec2:us-west-2:123456789012:instance/i-0123456789abcdef0"
+	if matchingResource.CloudSvc != "ec2" || matchingResource.RID != expectedRID || !slices.Equal(ipAddrs, []string{"10.0.2.7"}) || matchingResource.Tags != nil {
+		t.Errorf("Converting AWS Config network interface failed; received %+v with IPs %v", matchingResource, ipAddrs)
+	}
+}
+
+func TestConvertEIPItem(t *testing.T) {
+	var tests = []struct {
+		testName, associationID string
+		expectedIPAddrs         []string
+		expectedRID             string
+	}{
+		{"unassociated", "null", []string{"203.0.113.20"}, "eipalloc-0123456789abcdef0"},
+		// the network interface the EIP is associated with is reported instead
+		{"associated", `"eipassoc-0123456789abcdef0"`, nil, ""},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			matchingResource, ipAddrs, err := plugin.ConfigPlugin{}.ConvertEIPItem(parseConfigItem(t, fmt.Sprintf(testEIPResult, td.associationID)))
+			if err != nil {
+				t.Fatalf("Converting AWS Config elastic IP failed: %s", err)
+			}
+
+			if !slices.Equal(ipAddrs, td.expectedIPAddrs) || matchingResource.RID != td.expectedRID {
+				t.Errorf("Converting AWS Config elastic IP failed; expected %s with IPs %v, received %s with IPs %v", td.expectedRID, td.expectedIPAddrs, matchingResource.RID, ipAddrs)
+			}
+		})
+	}
+
+	matchingResource, _, _ := plugin.ConfigPlugin{}.ConvertEIPItem(parseConfigItem(t, fmt.Sprintf(testEIPResult, "null")))
+	expectedAttributes := map[string]string{"Source": "config", "PublicIpv4Pool": "amazon", "PublicIpv4PoolType": "amazon"}
+	if matchingResource.Name != "bastion" || !maps.Equal(matchingResource.Attributes, expectedAttributes) {
+		t.Errorf("Converting AWS Config elastic IP failed; received name %s and attributes %v", matchingResource.Name, matchingResource.Attributes)
+	}
+}
@@ -2,19 +2,54 @@ package plugin
 
 import (
 	"context"
+	"slices"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	eksp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eks"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type EC2Plugin struct {
 	AwsConn        awsconnector.AWSConnector
 	NetworkMapping bool
+	PrivateSearch  bool
+	VpcID          string
+}
+
+func init() {
+	registry.Register("aws", "ec2", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return EC2Plugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping, PrivateSearch: awsCfg.PrivateSearch, VpcID: awsCfg.VpcID}
+	}, registry.PrivateSearch)
+}
+
+func (ec2p EC2Plugin) GetEnabledRegions() ([]string, error) {
+	var regions []string
+
+	ec2Client := ec2.NewFromConfig(ec2p.AwsConn.AwsConfig)
+
+	// only regions that are enabled for the account are returned by default, which is exactly what we want here
+	output, err := ec2Client.DescribeRegions(context.TODO(), &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return regions, err
+	}
+
+	for _, region := range output.Regions {
+		regions = append(regions, *region.RegionName)
+	}
+
+	return regions, nil
 }
 
 func (ec2p EC2Plugin) GetResources() ([]types.Reservation, error) {
@@ -35,43 +70,198 @@ func (ec2p EC2Plugin) GetResources() ([]types.Reservation, error) {
 	return instances, nil
 }
 
-func (ec2p EC2Plugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
-	var matchingResource generalResource.Resource
+// InstanceENI holds the public (or private) IPs and delegated prefixes carried by one of an instance's network interfaces
+type InstanceENI struct {
+	NetworkInterfaceId, SubnetId, VpcId string
+	IPAddrs, Prefixes                   []string
+}
+
+func GetInstanceENIs(instance types.Instance) []InstanceENI {
+	return getInstanceENIs(instance, false)
+}
+
+// GetPrivateInstanceENIs is the same as GetInstanceENIs, but with the private IPv4 addresses of each interface in place of its public ones
+func GetPrivateInstanceENIs(instance types.Instance) []InstanceENI {
+	return getInstanceENIs(instance, true)
+}
+
+func getInstanceENIs(instance types.Instance, private bool) []InstanceENI {
+	var instanceENIs []InstanceENI
+
+	for _, eni := range instance.NetworkInterfaces {
+		instanceENI := InstanceENI{
+			NetworkInterfaceId: aws.ToString(eni.NetworkInterfaceId),
+			SubnetId:           aws.ToString(eni.SubnetId),
+			VpcId:              aws.ToString(eni.VpcId),
+		}
+
+		if private {
+			for _, privateIPAddr := range eni.PrivateIpAddresses {
+				if privateIPAddr.PrivateIpAddress != nil {
+					instanceENI.IPAddrs = append(instanceENI.IPAddrs, *privateIPAddr.PrivateIpAddress)
+				}
+			}
+		} else {
+			// the primary public IP is also listed under the primary private IP's association, so duplicates need to be skipped
+			ipv4Assocs := []*types.InstanceNetworkInterfaceAssociation{eni.Association}
+			for _, privateIPAddr := range eni.PrivateIpAddresses {
+				ipv4Assocs = append(ipv4Assocs, privateIPAddr.Association)
+			}
+			for _, ipv4Assoc := range ipv4Assocs {
+				if ipv4Assoc != nil && ipv4Assoc.PublicIp != nil && !slices.Contains(instanceENI.IPAddrs, *ipv4Assoc.PublicIp) {
+					instanceENI.IPAddrs = append(instanceENI.IPAddrs, *ipv4Assoc.PublicIp)
+				}
+			}
+		}
+
+		for _, ipv6Addr := range eni.Ipv6Addresses {
+			if ipv6Addr.Ipv6Address != nil {
+				instanceENI.IPAddrs = append(instanceENI.IPAddrs, *ipv6Addr.Ipv6Address)
+			}
+		}
+
+		for _, ipv4Prefix := range eni.Ipv4Prefixes {
+			if ipv4Prefix.Ipv4Prefix != nil {
+				instanceENI.Prefixes = append(instanceENI.Prefixes, *ipv4Prefix.Ipv4Prefix)
+			}
+		}
+		for _, ipv6Prefix := range eni.Ipv6Prefixes {
+			if ipv6Prefix.Ipv6Prefix != nil {
+				instanceENI.Prefixes = append(instanceENI.Prefixes, *ipv6Prefix.Ipv6Prefix)
+			}
+		}
+
+		instanceENIs = append(instanceENIs, instanceENI)
+	}
+
+	if len(instanceENIs) == 0 {
+		// interface details aren't always available (e.g. EC2-Classic), so fall back to the instance's primary IPs
+		instanceENI := InstanceENI{SubnetId: aws.ToString(instance.SubnetId), VpcId: aws.ToString(instance.VpcId)}
+		ipv4AddrPtr := instance.PublicIpAddress
+		if private {
+			ipv4AddrPtr = instance.PrivateIpAddress
+		}
+		for _, addrPtr := range []*string{ipv4AddrPtr, instance.Ipv6Address} {
+			if addrPtr != nil && *addrPtr != "" {
+				instanceENI.IPAddrs = append(instanceENI.IPAddrs, *addrPtr)
+			}
+		}
+
+		instanceENIs = append(instanceENIs, instanceENI)
+	}
+
+	return instanceENIs
+}
+
+func AddInstanceDetails(matchingResource *generalResource.Resource, instance types.Instance) {
+	matchingResource.Id = aws.ToString(instance.InstanceId)
+	matchingResource.Tags = utils.FormatTags(instance.Tags, TagKeyValue)
+	matchingResource.Name = matchingResource.Tags["Name"]
+	if instance.State != nil {
+		matchingResource.Status = string(instance.State.Name)
+	}
+
+	matchingResource.AddAttribute("InstanceType", string(instance.InstanceType))
+	if instance.LaunchTime != nil {
+		matchingResource.AddAttribute("LaunchTime", instance.LaunchTime.UTC().Format(time.RFC3339))
+	}
+	if instance.IamInstanceProfile != nil {
+		matchingResource.AddAttribute("IamInstanceProfile", aws.ToString(instance.IamInstanceProfile.Arn))
+	}
+}
+
+func (ec2p EC2Plugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
 
 	ec2Resources, err := ec2p.GetResources()
 	if err != nil {
-		return matchingResource, err
+		return matchingResources, err
 	}
 
-	var publicIPv4Addr, IPv6Addr string
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
 	for _, ec2Reservation := range ec2Resources {
 		// unpack instances from reservation
 		for _, instance := range ec2Reservation.Instances {
-			evalAddrPtr := func(addrPtr *string) string {
-				addr := ""
-				if addrPtr != nil {
-					addr = *addrPtr
+			var instanceIPAddrs, matchedTgts []string
+			tgtENIs := map[string][]InstanceENI{}
+
+			instanceENIs := GetInstanceENIs(instance)
+			if ec2p.PrivateSearch {
+				instanceENIs = GetPrivateInstanceENIs(instance)
+			}
+
+			for _, instanceENI := range instanceENIs {
+				// instances can have interfaces attached from other VPCs, so the VPC is checked per interface rather than per instance
+				if ec2p.VpcID != "" && instanceENI.VpcId != ec2p.VpcID {
+					continue
+				}
+
+				instanceIPAddrs = append(instanceIPAddrs, instanceENI.IPAddrs...)
+
+				// IPs within delegated prefixes aren't listed individually, so they can only be matched by containment
+				eniTgts := ipMatcher.MatchAny(instanceENI.IPAddrs)
+				for _, prefix := range instanceENI.Prefixes {
+					eniTgts = append(eniTgts, ipMatcher.MatchPrefix(prefix)...)
 				}
 
-				return addr
+				for _, tgt := range eniTgts {
+					if _, found := tgtENIs[tgt]; !found {
+						matchedTgts = append(matchedTgts, tgt)
+					}
+
+					if !slices.ContainsFunc(tgtENIs[tgt], func(tgtENI InstanceENI) bool {
+						return tgtENI.NetworkInterfaceId == instanceENI.NetworkInterfaceId
+					}) {
+						tgtENIs[tgt] = append(tgtENIs[tgt], instanceENI)
+					}
+				}
 			}
-			publicIPv4Addr = evalAddrPtr(instance.PublicIpAddress)
-			IPv6Addr = evalAddrPtr(instance.Ipv6Address)
 
-			if publicIPv4Addr == tgtIP || IPv6Addr == tgtIP {
+			for _, tgt := range matchedTgts {
+				var matchingResource generalResource.Resource
+				var eniIDs []string
+
 				matchingResource.RID = *instance.InstanceId // for some reason, the EC2 Instance object doesn't contain the ARN of the instance :/
 				matchingResource.CloudSvc = "ec2"
+				AddInstanceDetails(&matchingResource, instance)
 
-				if ec2p.NetworkMapping {
-					matchingResource.NetworkMap = append(matchingResource.NetworkMap, *instance.VpcId, *instance.SubnetId, *instance.InstanceId)
+				// EKS worker nodes are tagged with the cluster and nodegroup they belong to
+				k8sOwner := eksp.ParseKubernetesTags(matchingResource.Tags)
+				eksp.AddKubernetesDetails(&matchingResource, k8sOwner)
+
+				for _, ipAddr := range instanceIPAddrs {
+					matchingResource.AddIPAddr(ipAddr, ec2p.PrivateSearch)
 				}
 
-				log.Debug("IP found as EC2 instance -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+				// record which interface(s) carried the address, since it's not always the primary one
+				for _, tgtENI := range tgtENIs[tgt] {
+					if tgtENI.NetworkInterfaceId != "" {
+						eniIDs = append(eniIDs, tgtENI.NetworkInterfaceId)
+					}
+				}
+				matchingResource.AddAttribute("NetworkInterfaceId", strings.Join(eniIDs, ","))
 
-				break
+				if ec2p.NetworkMapping {
+					carrierENI := tgtENIs[tgt][0]
+					matchingResource.NetworkMap = append(matchingResource.NetworkMap, carrierENI.VpcId, carrierENI.SubnetId)
+					if carrierENI.NetworkInterfaceId != "" {
+						matchingResource.NetworkMap = append(matchingResource.NetworkMap, carrierENI.NetworkInterfaceId)
+					}
+					matchingResource.NetworkMap = append(matchingResource.NetworkMap, *instance.InstanceId)
+
+					eksp.EKSPlugin{AwsConn: ec2p.AwsConn}.MapNetwork(&matchingResource, k8sOwner)
+				}
+
+				log.Debug("IP ", tgt, " found as EC2 instance -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+				matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
 			}
 		}
 	}
 
-	return matchingResource, nil
+	return matchingResources, nil
+}
+
+func (ec2p EC2Plugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(ec2p, tgtIP)
 }
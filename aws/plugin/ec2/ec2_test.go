@@ -1,11 +1,19 @@
 package plugin_test
 
 import (
+	"maps"
 	"reflect"
+	"slices"
 	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
 	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/ec2"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 func ec2pFactory() plugin.EC2Plugin {
@@ -56,3 +64,232 @@ func TestSearchResources(t *testing.T) {
 		})
 	}
 }
+
+func TestTagKeyValue(t *testing.T) {
+	tags := []types.Tag{
+		{Key: aws.String("Name"), Value: aws.String("web01")},
+		{Key: aws.String("env"), Value: aws.String("prod")},
+	}
+
+	expectedTags := map[string]string{"Name": "web01", "env": "prod"}
+	if formattedTags := utils.FormatTags(tags, plugin.TagKeyValue); !maps.Equal(formattedTags, expectedTags) {
+		t.Errorf("Formatting EC2 tags failed; expected %v, received %v", expectedTags, formattedTags)
+	}
+}
+
+func TestGetIPv4PoolType(t *testing.T) {
+	var tests = []struct {
+		publicIPv4Pool, expectedPoolType string
+	}{
+		{"amazon", "amazon"},
+		{"", "amazon"},
+		{"ipv4pool-ec2-012345abcdef67890", "byoip"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.publicIPv4Pool, func(t *testing.T) {
+			poolType := plugin.GetIPv4PoolType(td.publicIPv4Pool)
+			if poolType != td.expectedPoolType {
+				t.Errorf("Determining IPv4 pool type failed; expected %s, received %s", td.expectedPoolType, poolType)
+			}
+		})
+	}
+}
+
+func TestEIPSearchResources(t *testing.T) {
+	ac, _ := awsconnector.New()
+	eipp := plugin.EIPPlugin{AwsConn: ac}
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.ipAddr, func(t *testing.T) {
+			matchedEIP, _ := eipp.SearchResources(td.ipAddr)
+			matchedEIPType := reflect.TypeOf(matchedEIP)
+
+			if matchedEIPType.Name() != td.expectedType {
+				t.Errorf("EIP search failed; expected %s after search, received %s", td.expectedType, matchedEIPType.Name())
+			}
+		})
+	}
+}
+
+func TestGetImplicitSubnets(t *testing.T) {
+	subnets := []types.Subnet{{SubnetId: aws.String("subnet-a")}, {SubnetId: aws.String("subnet-b")}, {SubnetId: aws.String("subnet-c")}, {SubnetId: aws.String("subnet-d")}}
+	vpcRouteTables := []types.RouteTable{
+		{RouteTableId: aws.String("rtb-private"), Associations: []types.RouteTableAssociation{{SubnetId: aws.String("subnet-a")}}},
+		{RouteTableId: aws.String("rtb-main"), Associations: []types.RouteTableAssociation{{Main: aws.Bool(true)}, {SubnetId: aws.String("subnet-c")}}},
+	}
+
+	expectedSubnets := []string{"subnet-b", "subnet-d"}
+	if implicitSubnets := plugin.GetImplicitSubnets(subnets, vpcRouteTables); !slices.Equal(implicitSubnets, expectedSubnets) {
+		t.Errorf("Determining subnets using the main route table failed; expected %v, received %v", expectedSubnets, implicitSubnets)
+	}
+}
+
+func TestFormatEgressRoutes(t *testing.T) {
+	natGatewayID := "nat-0123456789abcdef0"
+	routeTables := []types.RouteTable{
+		{
+			RouteTableId: aws.String("rtb-private"),
+			Routes:       []types.Route{{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String(natGatewayID)}},
+			Associations: []types.RouteTableAssociation{{SubnetId: aws.String("subnet-a")}, {SubnetId: aws.String("subnet-b")}},
+		},
+		{
+			RouteTableId: aws.String("rtb-main"),
+			Routes:       []types.Route{{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String(natGatewayID)}},
+			Associations: []types.RouteTableAssociation{{Main: aws.Bool(true)}, {SubnetId: aws.String("subnet-c")}},
+		},
+		{
+			// only routes a specific prefix through the gateway, so it isn't used for general egress
+			RouteTableId: aws.String("rtb-partner"),
+			Routes:       []types.Route{{DestinationCidrBlock: aws.String("203.0.113.0/24"), NatGatewayId: aws.String(natGatewayID)}},
+			Associations: []types.RouteTableAssociation{{SubnetId: aws.String("subnet-d")}},
+		},
+	}
+
+	expectedRoutes := []string{"rtb-private [subnet-a,subnet-b]", "rtb-main (main) [subnet-c,subnet-e,subnet-f]"}
+	if egressRoutes := plugin.FormatEgressRoutes(natGatewayID, routeTables, []string{"subnet-e", "subnet-f"}); !slices.Equal(egressRoutes, expectedRoutes) {
+		t.Errorf("Formatting NAT gateway egress routes failed; expected %v, received %v", expectedRoutes, egressRoutes)
+	}
+}
+
+func TestNATGatewaySearchResources(t *testing.T) {
+	ac, _ := awsconnector.New()
+	natgwp := plugin.NATGatewayPlugin{AwsConn: ac}
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.ipAddr, func(t *testing.T) {
+			matchedNATGateway, _ := natgwp.SearchResources(td.ipAddr)
+			matchedNATGatewayType := reflect.TypeOf(matchedNATGateway)
+
+			if matchedNATGatewayType.Name() != td.expectedType {
+				t.Errorf("NAT gateway search failed; expected %s after search, received %s", td.expectedType, matchedNATGatewayType.Name())
+			}
+		})
+	}
+}
+
+func TestGetInstanceENIs(t *testing.T) {
+	var tests = []struct {
+		testName    string
+		instance    types.Instance
+		expectedENI []plugin.InstanceENI
+	}{
+		{
+			"multipleENIs",
+			types.Instance{
+				NetworkInterfaces: []types.InstanceNetworkInterface{
+					{
+						NetworkInterfaceId: aws.String("eni-primary"),
+						SubnetId:           aws.String("subnet-a"),
+						VpcId:              aws.String("vpc-a"),
+						Association:        &types.InstanceNetworkInterfaceAssociation{PublicIp: aws.String("1.1.1.1")},
+						PrivateIpAddresses: []types.InstancePrivateIpAddress{
+							{Association: &types.InstanceNetworkInterfaceAssociation{PublicIp: aws.String("1.1.1.1")}},
+							{Association: &types.InstanceNetworkInterfaceAssociation{PublicIp: aws.String("1.1.1.2")}},
+						},
+						Ipv6Addresses: []types.InstanceIpv6Address{{Ipv6Address: aws.String("2600:1f18::1")}, {Ipv6Address: aws.String("2600:1f18::2")}},
+					},
+					{
+						NetworkInterfaceId: aws.String("eni-secondary"),
+						SubnetId:           aws.String("subnet-b"),
+						VpcId:              aws.String("vpc-a"),
+						Ipv4Prefixes:       []types.InstanceIpv4Prefix{{Ipv4Prefix: aws.String("10.0.0.16/28")}},
+						Ipv6Prefixes:       []types.InstanceIpv6Prefix{{Ipv6Prefix: aws.String("2600:1f18:0:1::/80")}},
+					},
+				},
+			},
+			[]plugin.InstanceENI{
+				{NetworkInterfaceId: "eni-primary", SubnetId: "subnet-a", VpcId: "vpc-a", IPAddrs: []string{"1.1.1.1", "1.1.1.2", "2600:1f18::1", "2600:1f18::2"}},
+				{NetworkInterfaceId: "eni-secondary", SubnetId: "subnet-b", VpcId: "vpc-a", Prefixes: []string{"10.0.0.16/28", "2600:1f18:0:1::/80"}},
+			},
+		},
+		{
+			"noENIDetails",
+			types.Instance{PublicIpAddress: aws.String("1.1.1.1"), SubnetId: aws.String("subnet-a"), VpcId: aws.String("vpc-a")},
+			[]plugin.InstanceENI{
+				{SubnetId: "subnet-a", VpcId: "vpc-a", IPAddrs: []string{"1.1.1.1"}},
+			},
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			instanceENIs := plugin.GetInstanceENIs(td.instance)
+
+			if !reflect.DeepEqual(instanceENIs, td.expectedENI) {
+				t.Errorf("Fetching instance ENIs failed; expected %+v, received %+v", td.expectedENI, instanceENIs)
+			}
+		})
+	}
+}
+
+func TestGetPrivateInstanceENIs(t *testing.T) {
+	instance := types.Instance{
+		NetworkInterfaces: []types.InstanceNetworkInterface{
+			{
+				NetworkInterfaceId: aws.String("eni-primary"),
+				SubnetId:           aws.String("subnet-a"),
+				VpcId:              aws.String("vpc-a"),
+				Association:        &types.InstanceNetworkInterfaceAssociation{PublicIp: aws.String("1.1.1.1")},
+				PrivateIpAddresses: []types.InstancePrivateIpAddress{
+					{PrivateIpAddress: aws.String("10.0.0.5"), Association: &types.InstanceNetworkInterfaceAssociation{PublicIp: aws.String("1.1.1.1")}},
+					{PrivateIpAddress: aws.String("10.0.0.6")},
+				},
+				Ipv6Addresses: []types.InstanceIpv6Address{{Ipv6Address: aws.String("2600:1f18::1")}},
+				Ipv4Prefixes:  []types.InstanceIpv4Prefix{{Ipv4Prefix: aws.String("10.0.0.16/28")}},
+			},
+		},
+	}
+
+	expectedENIs := []plugin.InstanceENI{
+		{NetworkInterfaceId: "eni-primary", SubnetId: "subnet-a", VpcId: "vpc-a", IPAddrs: []string{"10.0.0.5", "10.0.0.6", "2600:1f18::1"}, Prefixes: []string{"10.0.0.16/28"}},
+	}
+
+	instanceENIs := plugin.GetPrivateInstanceENIs(instance)
+	if !reflect.DeepEqual(instanceENIs, expectedENIs) {
+		t.Errorf("Fetching private instance ENIs failed; expected %+v, received %+v", expectedENIs, instanceENIs)
+	}
+}
+
+func TestAddInstanceDetails(t *testing.T) {
+	var matchingResource generalResource.Resource
+
+	launchTime := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	plugin.AddInstanceDetails(&matchingResource, types.Instance{
+		InstanceId:         aws.String("i-0123456789abcdef0"),
+		InstanceType:       types.InstanceTypeT3Micro,
+		LaunchTime:         &launchTime,
+		State:              &types.InstanceState{Name: types.InstanceStateNameRunning},
+		IamInstanceProfile: &types.IamInstanceProfile{Arn: aws.String("arn:aws:iam::123456789012:instance-profile/web")},
+		Tags:               []types.Tag{{Key: aws.String("Name"), Value: aws.String("web01")}},
+	})
+
+	expectedResource := generalResource.Resource{
+		Id:     "i-0123456789abcdef0",
+		Name:   "web01",
+		Status: "running",
+		Tags:   map[string]string{"Name": "web01"},
+		Attributes: map[string]string{
+			"InstanceType":       "t3.micro",
+			"LaunchTime":         "2024-03-01T12:00:00Z",
+			"IamInstanceProfile": "arn:aws:iam::123456789012:instance-profile/web",
+		},
+	}
+	if !reflect.DeepEqual(matchingResource, expectedResource) {
+		t.Errorf("Adding EC2 instance details failed; expected %+v, received %+v", expectedResource, matchingResource)
+	}
+}
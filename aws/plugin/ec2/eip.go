@@ -0,0 +1,118 @@
+package plugin
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+const (
+	EIPAssociatedStatus   = "associated"
+	EIPUnassociatedStatus = "unassociated"
+)
+
+type EIPPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	NetworkMapping bool
+}
+
+func init() {
+	// elastic IPs
+	registry.Register("aws", "eip", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return EIPPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping}
+	})
+}
+
+func TagKeyValue(tag types.Tag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func GetIPv4PoolType(publicIPv4Pool string) string {
+	// addresses from Amazon's pool are all in the "amazon" pool, while BYOIP pools are assigned their own ID (e.g. ipv4pool-ec2-...)
+	if publicIPv4Pool == "" || publicIPv4Pool == "amazon" {
+		return "amazon"
+	}
+
+	return "byoip"
+}
+
+func (eipp EIPPlugin) GetResources() ([]types.Address, error) {
+	ec2Client := ec2.NewFromConfig(eipp.AwsConn.AwsConfig)
+
+	// DescribeAddresses doesn't support pagination; every address in the region is returned at once
+	output, err := ec2Client.DescribeAddresses(context.TODO(), &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	return output.Addresses, nil
+}
+
+func (eipp EIPPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	eipResources, err := eipp.GetResources()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	for _, eip := range eipResources {
+		eipIPAddr := aws.ToString(eip.PublicIp)
+
+		for _, tgt := range ipMatcher.Match(eipIPAddr) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(eip.AllocationId)
+			matchingResource.RID = matchingResource.Id
+			matchingResource.CloudSvc = "eip"
+			matchingResource.Tags = utils.FormatTags(eip.Tags, TagKeyValue)
+			matchingResource.Name = matchingResource.Tags["Name"]
+			matchingResource.AddPublicIPAddr(eipIPAddr)
+
+			matchingResource.AddAttribute("AssociationId", aws.ToString(eip.AssociationId))
+			matchingResource.AddAttribute("InstanceId", aws.ToString(eip.InstanceId))
+			matchingResource.AddAttribute("NetworkInterfaceId", aws.ToString(eip.NetworkInterfaceId))
+			matchingResource.AddAttribute("PrivateIpAddress", aws.ToString(eip.PrivateIpAddress))
+			matchingResource.AddAttribute("PublicIpv4Pool", aws.ToString(eip.PublicIpv4Pool))
+			matchingResource.AddAttribute("PublicIpv4PoolType", GetIPv4PoolType(aws.ToString(eip.PublicIpv4Pool)))
+
+			if eip.AssociationId == nil {
+				// the address is still billed to, and reserved for, the account, but nothing is using it
+				matchingResource.Status = EIPUnassociatedStatus
+				log.Warn("IP ", eipIPAddr, " is an elastic IP (", matchingResource.Id, ") that's allocated to the account, but not associated with any resource")
+			} else {
+				matchingResource.Status = EIPAssociatedStatus
+			}
+
+			if eipp.NetworkMapping {
+				for _, networkResource := range []*string{eip.NetworkInterfaceId, eip.InstanceId} {
+					if networkResource != nil {
+						matchingResource.NetworkMap = append(matchingResource.NetworkMap, *networkResource)
+					}
+				}
+			}
+
+			log.Debug("IP ", tgt, " found as elastic IP -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (eipp EIPPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(eipp, tgtIP)
+}
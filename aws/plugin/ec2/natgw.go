@@ -0,0 +1,257 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+const defaultRouteCIDR = "0.0.0.0/0"
+
+type NATGatewayPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	NetworkMapping bool
+}
+
+func init() {
+	registry.Register("aws", "natgw", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return NATGatewayPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping}
+	})
+}
+
+func (natgwp NATGatewayPlugin) GetResources() ([]types.NatGateway, error) {
+	var natGateways []types.NatGateway
+
+	ec2Client := ec2.NewFromConfig(natgwp.AwsConn.AwsConfig)
+	paginator := ec2.NewDescribeNatGatewaysPaginator(ec2Client, nil)
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return natGateways, err
+		}
+
+		natGateways = append(natGateways, output.NatGateways...)
+	}
+
+	return natGateways, nil
+}
+
+func (natgwp NATGatewayPlugin) GetEgressRouteTables(natGatewayID string) ([]types.RouteTable, error) {
+	var routeTables []types.RouteTable
+
+	ec2Client := ec2.NewFromConfig(natgwp.AwsConn.AwsConfig)
+	paginator := ec2.NewDescribeRouteTablesPaginator(ec2Client, &ec2.DescribeRouteTablesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("route.nat-gateway-id"), Values: []string{natGatewayID}},
+		},
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return routeTables, err
+		}
+
+		routeTables = append(routeTables, output.RouteTables...)
+	}
+
+	return routeTables, nil
+}
+
+func (natgwp NATGatewayPlugin) GetVpcSubnets(vpcID string) ([]types.Subnet, error) {
+	var subnets []types.Subnet
+
+	ec2Client := ec2.NewFromConfig(natgwp.AwsConn.AwsConfig)
+	paginator := ec2.NewDescribeSubnetsPaginator(ec2Client, &ec2.DescribeSubnetsInput{
+		Filters: []types.Filter{
+			{Name: aws.String("vpc-id"), Values: []string{vpcID}},
+		},
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return subnets, err
+		}
+
+		subnets = append(subnets, output.Subnets...)
+	}
+
+	return subnets, nil
+}
+
+func (natgwp NATGatewayPlugin) GetVpcRouteTables(vpcID string) ([]types.RouteTable, error) {
+	var routeTables []types.RouteTable
+
+	ec2Client := ec2.NewFromConfig(natgwp.AwsConn.AwsConfig)
+	paginator := ec2.NewDescribeRouteTablesPaginator(ec2Client, &ec2.DescribeRouteTablesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("vpc-id"), Values: []string{vpcID}},
+		},
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return routeTables, err
+		}
+
+		routeTables = append(routeTables, output.RouteTables...)
+	}
+
+	return routeTables, nil
+}
+
+func isMainRouteTable(routeTable types.RouteTable) bool {
+	return slices.ContainsFunc(routeTable.Associations, func(association types.RouteTableAssociation) bool {
+		return aws.ToBool(association.Main)
+	})
+}
+
+// GetImplicitSubnets determines which subnets use the VPC's main route table, i.e. those that aren't explicitly associated with any route table
+func GetImplicitSubnets(subnets []types.Subnet, vpcRouteTables []types.RouteTable) []string {
+	explicitSubnets := map[string]bool{}
+	for _, routeTable := range vpcRouteTables {
+		for _, association := range routeTable.Associations {
+			if association.SubnetId != nil {
+				explicitSubnets[*association.SubnetId] = true
+			}
+		}
+	}
+
+	var implicitSubnets []string
+	for _, subnet := range subnets {
+		if subnetID := aws.ToString(subnet.SubnetId); !explicitSubnets[subnetID] {
+			implicitSubnets = append(implicitSubnets, subnetID)
+		}
+	}
+
+	return implicitSubnets
+}
+
+func (natgwp NATGatewayPlugin) GetImplicitlyRoutedSubnets(vpcID string) ([]string, error) {
+	subnets, err := natgwp.GetVpcSubnets(vpcID)
+	if err != nil {
+		return nil, err
+	}
+
+	vpcRouteTables, err := natgwp.GetVpcRouteTables(vpcID)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetImplicitSubnets(subnets, vpcRouteTables), nil
+}
+
+// FormatEgressRoutes lists each route table that sends internet-bound traffic through the NAT gateway, along with the subnets using it, e.g. rtb-0123 [subnet-0123,subnet-4567]; the main route table is marked as such and includes the subnets that implicitly use it
+func FormatEgressRoutes(natGatewayID string, routeTables []types.RouteTable, implicitSubnets []string) []string {
+	var egressRoutes []string
+
+	for _, routeTable := range routeTables {
+		defaultRouteFound := false
+		for _, route := range routeTable.Routes {
+			if aws.ToString(route.DestinationCidrBlock) == defaultRouteCIDR && aws.ToString(route.NatGatewayId) == natGatewayID {
+				defaultRouteFound = true
+				break
+			}
+		}
+		if !defaultRouteFound {
+			continue
+		}
+
+		var routedSubnets []string
+		for _, association := range routeTable.Associations {
+			if association.SubnetId != nil {
+				routedSubnets = append(routedSubnets, *association.SubnetId)
+			}
+		}
+
+		routeTableID := aws.ToString(routeTable.RouteTableId)
+		if isMainRouteTable(routeTable) {
+			routeTableID += " (main)"
+			routedSubnets = append(routedSubnets, implicitSubnets...)
+		}
+
+		egressRoutes = append(egressRoutes, fmt.Sprintf("%s %s", routeTableID, utils.FormatStrSliceAsCSV(routedSubnets)))
+	}
+
+	return egressRoutes
+}
+
+func (natgwp NATGatewayPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	natGateways, err := natgwp.GetResources()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	for _, natGateway := range natGateways {
+		// gateways can have secondary IPs in addition to their primary one, all of which can be used for egress
+		var natGatewayIPAddrs []string
+		for _, natGatewayAddr := range natGateway.NatGatewayAddresses {
+			if natGatewayAddr.PublicIp != nil {
+				natGatewayIPAddrs = append(natGatewayIPAddrs, *natGatewayAddr.PublicIp)
+			}
+		}
+
+		for _, tgt := range ipMatcher.MatchAny(natGatewayIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(natGateway.NatGatewayId)
+			matchingResource.RID = matchingResource.Id
+			matchingResource.CloudSvc = "natgw"
+			matchingResource.Status = string(natGateway.State)
+			matchingResource.Tags = utils.FormatTags(natGateway.Tags, TagKeyValue)
+			matchingResource.Name = matchingResource.Tags["Name"]
+
+			for _, ipAddr := range natGatewayIPAddrs {
+				matchingResource.AddPublicIPAddr(ipAddr)
+			}
+
+			if natgwp.NetworkMapping {
+				routeTables, err := natgwp.GetEgressRouteTables(matchingResource.Id)
+				if err != nil {
+					return matchingResources, err
+				}
+
+				// subnets without an explicit association use the VPC's main route table, so they're only known by listing every subnet in the VPC
+				var implicitSubnets []string
+				if slices.ContainsFunc(routeTables, isMainRouteTable) {
+					implicitSubnets, err = natgwp.GetImplicitlyRoutedSubnets(aws.ToString(natGateway.VpcId))
+					if err != nil {
+						return matchingResources, err
+					}
+				}
+
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(natGateway.VpcId), aws.ToString(natGateway.SubnetId), matchingResource.Id)
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, FormatEgressRoutes(matchingResource.Id, routeTables, implicitSubnets)...)
+			}
+
+			log.Debug("IP ", tgt, " found as NAT gateway -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (natgwp NATGatewayPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(natgwp, tgtIP)
+}
@@ -0,0 +1,250 @@
+package plugin
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	enip "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eni"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// ECS limits the number of tasks that can be described in a single call
+const maxDescribeTasks = 100
+
+type ECSPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	NetworkMapping bool
+}
+
+func init() {
+	// only tasks using awsvpc networking (incl. every Fargate task) get their own ENI; tasks using bridge or host networking share the IPs of their container instance
+	registry.Register("aws", "ecs", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return ECSPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping}
+	})
+}
+
+func tagKeyValue(tag types.Tag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func GetResourceID(resourceArn string) string {
+	// task and service ARNs are either <type>/<cluster>/<id> or, in the older format, <type>/<id>
+	return resourceArn[strings.LastIndex(resourceArn, "/")+1:]
+}
+
+func GetServiceName(task types.Task) string {
+	// tasks started by a service are grouped as service:<service name>
+	serviceName, found := strings.CutPrefix(aws.ToString(task.Group), "service:")
+	if !found {
+		return ""
+	}
+
+	return serviceName
+}
+
+func GetENIAttachmentIDs(task types.Task) []string {
+	var attachmentIDs []string
+	for _, attachment := range task.Attachments {
+		if aws.ToString(attachment.Type) == "ElasticNetworkInterface" && attachment.Id != nil {
+			attachmentIDs = append(attachmentIDs, *attachment.Id)
+		}
+	}
+
+	return attachmentIDs
+}
+
+func (ecsp ECSPlugin) GetClusters() ([]string, error) {
+	var clusterArns []string
+
+	ecsClient := ecs.NewFromConfig(ecsp.AwsConn.AwsConfig)
+	paginator := ecs.NewListClustersPaginator(ecsClient, &ecs.ListClustersInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return clusterArns, err
+		}
+
+		clusterArns = append(clusterArns, output.ClusterArns...)
+	}
+
+	return clusterArns, nil
+}
+
+// GetServices maps the name of each service in the cluster to its ARN
+func (ecsp ECSPlugin) GetServices(clusterArn string) (map[string]string, error) {
+	serviceArns := map[string]string{}
+
+	ecsClient := ecs.NewFromConfig(ecsp.AwsConn.AwsConfig)
+	paginator := ecs.NewListServicesPaginator(ecsClient, &ecs.ListServicesInput{
+		Cluster: &clusterArn,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return serviceArns, err
+		}
+
+		for _, serviceArn := range output.ServiceArns {
+			serviceArns[GetResourceID(serviceArn)] = serviceArn
+		}
+	}
+
+	return serviceArns, nil
+}
+
+func (ecsp ECSPlugin) GetTasks(clusterArn string) ([]types.Task, error) {
+	var taskArns []string
+	var tasks []types.Task
+
+	ecsClient := ecs.NewFromConfig(ecsp.AwsConn.AwsConfig)
+	paginator := ecs.NewListTasksPaginator(ecsClient, &ecs.ListTasksInput{
+		Cluster: &clusterArn,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return tasks, err
+		}
+
+		taskArns = append(taskArns, output.TaskArns...)
+	}
+
+	// ListTasks only returns ARNs, so the details of each task have to be fetched separately
+	for taskArnChunk := range slices.Chunk(taskArns, maxDescribeTasks) {
+		output, err := ecsClient.DescribeTasks(context.TODO(), &ecs.DescribeTasksInput{
+			Cluster: &clusterArn,
+			Tasks:   taskArnChunk,
+			Include: []types.TaskField{types.TaskFieldTags},
+		})
+		if err != nil {
+			return tasks, err
+		}
+
+		tasks = append(tasks, output.Tasks...)
+	}
+
+	return tasks, nil
+}
+
+func (ecsp ECSPlugin) GetResources() ([]types.Task, error) {
+	var tasks []types.Task
+
+	clusterArns, err := ecsp.GetClusters()
+	if err != nil {
+		return tasks, err
+	}
+
+	for _, clusterArn := range clusterArns {
+		clusterTasks, err := ecsp.GetTasks(clusterArn)
+		if err != nil {
+			return tasks, err
+		}
+
+		tasks = append(tasks, clusterTasks...)
+	}
+
+	return tasks, nil
+}
+
+func AddTaskDetails(matchingResource *generalResource.Resource, task types.Task) {
+	matchingResource.Id = GetResourceID(aws.ToString(task.TaskArn))
+	matchingResource.RID = aws.ToString(task.TaskArn)
+	matchingResource.CloudSvc = "ecs"
+	matchingResource.Status = aws.ToString(task.LastStatus)
+	matchingResource.Tags = utils.FormatTags(task.Tags, tagKeyValue)
+
+	matchingResource.AddAttribute("ClusterArn", aws.ToString(task.ClusterArn))
+	matchingResource.AddAttribute("TaskDefinitionArn", aws.ToString(task.TaskDefinitionArn))
+	matchingResource.AddAttribute("ServiceName", GetServiceName(task))
+	matchingResource.AddAttribute("LaunchType", string(task.LaunchType))
+	matchingResource.AddAttribute("CapacityProviderName", aws.ToString(task.CapacityProviderName))
+}
+
+func (ecsp ECSPlugin) mapNetwork(task types.Task, eniID string, serviceArns map[string]string, matchingResource *generalResource.Resource) {
+	matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(task.ClusterArn))
+
+	// standalone tasks (e.g. scheduled or run via RunTask) don't belong to a service
+	if serviceArn, found := serviceArns[GetServiceName(task)]; found {
+		matchingResource.NetworkMap = append(matchingResource.NetworkMap, serviceArn)
+	}
+
+	matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(task.TaskArn), eniID)
+}
+
+func (ecsp ECSPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	tasks, err := ecsp.GetResources()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	// task attachments only include the private IPs of the ENI, so public IPs have to be looked up via the ENI itself
+	eniPlugin := enip.ENIPlugin{AwsConn: ecsp.AwsConn}
+	attachmentENIs, err := eniPlugin.GetECSAttachmentENIs()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	// services are only listed for clusters with a matching task, and only once per cluster
+	clusterServiceArns := map[string]map[string]string{}
+
+	for _, task := range tasks {
+		for _, attachmentID := range GetENIAttachmentIDs(task) {
+			eni, found := attachmentENIs[attachmentID]
+			if !found {
+				continue
+			}
+			eniIPAddrs := enip.GetPublicIPAddrs(eni)
+
+			for _, tgt := range ipMatcher.MatchAny(eniIPAddrs) {
+				var matchingResource generalResource.Resource
+
+				AddTaskDetails(&matchingResource, task)
+				matchingResource.AddAttribute("NetworkInterfaceId", aws.ToString(eni.NetworkInterfaceId))
+
+				for _, ipAddr := range eniIPAddrs {
+					matchingResource.AddPublicIPAddr(ipAddr)
+				}
+
+				if ecsp.NetworkMapping {
+					clusterArn := aws.ToString(task.ClusterArn)
+					if _, found := clusterServiceArns[clusterArn]; !found {
+						clusterServiceArns[clusterArn], err = ecsp.GetServices(clusterArn)
+						if err != nil {
+							return matchingResources, err
+						}
+					}
+
+					ecsp.mapNetwork(task, aws.ToString(eni.NetworkInterfaceId), clusterServiceArns[clusterArn], &matchingResource)
+				}
+
+				log.Debug("IP ", tgt, " found as ECS task -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+				matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+			}
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (ecsp ECSPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(ecsp, tgtIP)
+}
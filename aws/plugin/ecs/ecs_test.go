@@ -0,0 +1,120 @@
+package plugin_test
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/ecs"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func ecspFactory() plugin.ECSPlugin {
+	ac, _ := awsconnector.New()
+
+	ecsp := plugin.ECSPlugin{AwsConn: ac}
+
+	return ecsp
+}
+
+func TestGetResources(t *testing.T) {
+	ecsp := ecspFactory()
+
+	ecsResources, _ := ecsp.GetResources()
+
+	expectedType := "Task"
+	for _, task := range ecsResources {
+		taskType := reflect.TypeOf(task)
+		if taskType.Name() != expectedType {
+			t.Errorf("Fetching resources via ECS Plugin failed; wanted %s type, received %s", expectedType, taskType.Name())
+		}
+	}
+}
+
+func TestGetResourceID(t *testing.T) {
+	var tests = []struct {
+		resourceArn, expectedID string
+	}{
+		{"arn:aws:ecs:us-east-1:123456789012:task/my-cluster/0123456789abcdef0123456789abcdef", "0123456789abcdef0123456789abcdef"},
+		{"arn:aws:ecs:us-east-1:123456789012:task/0123456789abcdef", "0123456789abcdef"},
+		{"arn:aws:ecs:us-east-1:123456789012:service/my-cluster/web", "web"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.resourceArn, func(t *testing.T) {
+			if resourceID := plugin.GetResourceID(td.resourceArn); resourceID != td.expectedID {
+				t.Errorf("Parsing ECS resource ID failed; expected %s, received %s", td.expectedID, resourceID)
+			}
+		})
+	}
+}
+
+func TestGetServiceName(t *testing.T) {
+	var tests = []struct {
+		testName, group, expectedServiceName string
+	}{
+		{"service", "service:web", "web"},
+		{"standalone", "family:web-task", ""},
+		{"noGroup", "", ""},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			if serviceName := plugin.GetServiceName(types.Task{Group: aws.String(td.group)}); serviceName != td.expectedServiceName {
+				t.Errorf("Parsing ECS service name failed; expected %s, received %s", td.expectedServiceName, serviceName)
+			}
+		})
+	}
+}
+
+func TestGetENIAttachmentIDs(t *testing.T) {
+	task := types.Task{
+		Attachments: []types.Attachment{
+			{Id: aws.String("1a2b3c4d-1111-2222-3333-444455556666"), Type: aws.String("ElasticNetworkInterface")},
+			{Id: aws.String("5e6f7a8b-1111-2222-3333-444455556666"), Type: aws.String("Service Connect")},
+		},
+	}
+
+	expectedAttachmentIDs := []string{"1a2b3c4d-1111-2222-3333-444455556666"}
+	if attachmentIDs := plugin.GetENIAttachmentIDs(task); !slices.Equal(attachmentIDs, expectedAttachmentIDs) {
+		t.Errorf("Fetching ECS task ENI attachments failed; expected %v, received %v", expectedAttachmentIDs, attachmentIDs)
+	}
+}
+
+func TestAddTaskDetails(t *testing.T) {
+	var matchingResource generalResource.Resource
+
+	plugin.AddTaskDetails(&matchingResource, types.Task{
+		TaskArn:           aws.String("arn:aws:ecs:us-east-1:123456789012:task/my-cluster/0123456789abcdef"),
+		ClusterArn:        aws.String("arn:aws:ecs:us-east-1:123456789012:cluster/my-cluster"),
+		TaskDefinitionArn: aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/web:42"),
+		Group:             aws.String("service:web"),
+		LaunchType:        types.LaunchTypeFargate,
+		LastStatus:        aws.String("RUNNING"),
+		Tags:              []types.Tag{{Key: aws.String("team"), Value: aws.String("platform")}},
+	})
+
+	if matchingResource.Id != "0123456789abcdef" || matchingResource.CloudSvc != "ecs" || matchingResource.Status != "RUNNING" {
+		t.Errorf("Adding ECS task details failed; received %+v", matchingResource)
+	}
+
+	expectedAttributes := map[string]string{
+		"ClusterArn":        "arn:aws:ecs:us-east-1:123456789012:cluster/my-cluster",
+		"TaskDefinitionArn": "arn:aws:ecs:us-east-1:123456789012:task-definition/web:42",
+		"ServiceName":       "web",
+		"LaunchType":        "FARGATE",
+	}
+	if !maps.Equal(matchingResource.Attributes, expectedAttributes) {
+		t.Errorf("Adding ECS task details failed; expected attributes %v, received %v", expectedAttributes, matchingResource.Attributes)
+	}
+
+	expectedTags := map[string]string{"team": "platform"}
+	if !maps.Equal(matchingResource.Tags, expectedTags) {
+		t.Errorf("Adding ECS task details failed; expected tags %v, received %v", expectedTags, matchingResource.Tags)
+	}
+}
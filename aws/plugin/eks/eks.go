@@ -0,0 +1,133 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// tags that hold the name of the cluster, in order of precedence: the AWS Load Balancer Controller, EKS managed nodes, and the VPC CNI
+var clusterNameTags = []string{"elbv2.k8s.aws/cluster", "eks:cluster-name", "aws:eks:cluster-name", "cluster.k8s.amazonaws.com/name"}
+
+// the in-tree cloud provider and older tooling only tag resources with kubernetes.io/cluster/<cluster name>
+const clusterTagPrefix = "kubernetes.io/cluster/"
+
+// EKS-managed ENIs (e.g. for the cluster's control plane) are described as "Amazon EKS <cluster name>"
+const eksENIDescPrefix = "Amazon EKS "
+
+type EKSPlugin struct {
+	AwsConn awsconnector.AWSConnector
+}
+
+// KubernetesOwner describes the Kubernetes object and EKS cluster that an AWS resource was provisioned for
+type KubernetesOwner struct {
+	Cluster, Nodegroup, Namespace, Service, Ingress string
+}
+
+func parseObjectRef(objectRef string) (string, string) {
+	// objects are referenced as <namespace>/<name>; ingress groups are only referenced by the group's name
+	if namespace, name, found := strings.Cut(objectRef, "/"); found {
+		return namespace, name
+	}
+
+	return "", objectRef
+}
+
+// ParseKubernetesTags determines the owner from the tags set by the AWS Load Balancer Controller, the in-tree cloud provider, or EKS itself
+func ParseKubernetesTags(tags map[string]string) KubernetesOwner {
+	var k8sOwner KubernetesOwner
+
+	for _, clusterNameTag := range clusterNameTags {
+		if clusterName := tags[clusterNameTag]; clusterName != "" {
+			k8sOwner.Cluster = clusterName
+			break
+		}
+	}
+	if k8sOwner.Cluster == "" {
+		for tagKey := range tags {
+			if clusterName, found := strings.CutPrefix(tagKey, clusterTagPrefix); found && clusterName != "" {
+				k8sOwner.Cluster = clusterName
+				break
+			}
+		}
+	}
+
+	k8sOwner.Nodegroup = tags["eks:nodegroup-name"]
+
+	switch {
+	case tags["service.k8s.aws/stack"] != "":
+		k8sOwner.Namespace, k8sOwner.Service = parseObjectRef(tags["service.k8s.aws/stack"])
+	case tags["ingress.k8s.aws/stack"] != "":
+		k8sOwner.Namespace, k8sOwner.Ingress = parseObjectRef(tags["ingress.k8s.aws/stack"])
+	case tags["kubernetes.io/service-name"] != "":
+		k8sOwner.Namespace, k8sOwner.Service = parseObjectRef(tags["kubernetes.io/service-name"])
+	}
+
+	return k8sOwner
+}
+
+func ParseEKSENIDescription(eniDesc string) string {
+	if clusterName, found := strings.CutPrefix(eniDesc, eksENIDescPrefix); found {
+		return clusterName
+	}
+
+	return ""
+}
+
+func AddKubernetesDetails(matchingResource *generalResource.Resource, k8sOwner KubernetesOwner) {
+	matchingResource.AddAttribute("KubernetesCluster", k8sOwner.Cluster)
+	matchingResource.AddAttribute("KubernetesNodegroup", k8sOwner.Nodegroup)
+	matchingResource.AddAttribute("KubernetesNamespace", k8sOwner.Namespace)
+	matchingResource.AddAttribute("KubernetesService", k8sOwner.Service)
+	matchingResource.AddAttribute("KubernetesIngress", k8sOwner.Ingress)
+}
+
+func (eksp EKSPlugin) GetNodegroups(clusterName string) ([]string, error) {
+	var nodegroups []string
+
+	eksClient := eks.NewFromConfig(eksp.AwsConn.AwsConfig)
+	paginator := eks.NewListNodegroupsPaginator(eksClient, &eks.ListNodegroupsInput{
+		ClusterName: &clusterName,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nodegroups, err
+		}
+
+		nodegroups = append(nodegroups, output.Nodegroups...)
+	}
+
+	return nodegroups, nil
+}
+
+// MapNetwork adds the EKS cluster and the nodegroup(s) that could be serving the resource to its network map, e.g. eks:my-cluster -> [ng-1,ng-2]; the cluster is still mapped if its nodegroups can't be listed, since it may not be an EKS cluster (e.g. kops) or the role may lack access
+func (eksp EKSPlugin) MapNetwork(matchingResource *generalResource.Resource, k8sOwner KubernetesOwner) {
+	if k8sOwner.Cluster == "" {
+		return
+	}
+
+	matchingResource.NetworkMap = append(matchingResource.NetworkMap, fmt.Sprintf("eks:%s", k8sOwner.Cluster))
+
+	nodegroups := []string{k8sOwner.Nodegroup}
+	if k8sOwner.Nodegroup == "" {
+		// load balancers can send traffic to nodes in any of the cluster's nodegroups
+		var err error
+		nodegroups, err = eksp.GetNodegroups(k8sOwner.Cluster)
+		if err != nil {
+			log.Warn("unable to list nodegroups of Kubernetes cluster ", k8sOwner.Cluster, ", mapping cluster without them: ", err)
+			return
+		}
+	}
+
+	matchingResource.NetworkMap = append(matchingResource.NetworkMap, utils.FormatStrSliceAsCSV(nodegroups))
+}
@@ -0,0 +1,167 @@
+package plugin_test
+
+import (
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eks"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func TestParseKubernetesTags(t *testing.T) {
+	var tests = []struct {
+		testName         string
+		tags             map[string]string
+		expectedK8sOwner plugin.KubernetesOwner
+	}{
+		{
+			"lbController",
+			map[string]string{"elbv2.k8s.aws/cluster": "prod", "service.k8s.aws/stack": "web/frontend", "service.k8s.aws/resource": "LoadBalancer"},
+			plugin.KubernetesOwner{Cluster: "prod", Namespace: "web", Service: "frontend"},
+		},
+		{
+			"lbControllerIngress",
+			map[string]string{"elbv2.k8s.aws/cluster": "prod", "ingress.k8s.aws/stack": "web/api"},
+			plugin.KubernetesOwner{Cluster: "prod", Namespace: "web", Ingress: "api"},
+		},
+		{
+			"lbControllerIngressGroup",
+			map[string]string{"elbv2.k8s.aws/cluster": "prod", "ingress.k8s.aws/stack": "shared-alb"},
+			plugin.KubernetesOwner{Cluster: "prod", Ingress: "shared-alb"},
+		},
+		{
+			"inTreeCloudProvider",
+			map[string]string{"kubernetes.io/cluster/staging": "owned", "kubernetes.io/service-name": "default/nginx"},
+			plugin.KubernetesOwner{Cluster: "staging", Namespace: "default", Service: "nginx"},
+		},
+		{
+			"managedNode",
+			map[string]string{"eks:cluster-name": "prod", "eks:nodegroup-name": "ng-general", "kubernetes.io/cluster/prod": "owned"},
+			plugin.KubernetesOwner{Cluster: "prod", Nodegroup: "ng-general"},
+		},
+		{
+			"vpcCNI",
+			map[string]string{"cluster.k8s.amazonaws.com/name": "prod", "node.k8s.amazonaws.com/instance_id": "i-0123456789abcdef0"},
+			plugin.KubernetesOwner{Cluster: "prod"},
+		},
+		{"noKubernetesTags", map[string]string{"Name": "web-server"}, plugin.KubernetesOwner{}},
+		{"noTags", nil, plugin.KubernetesOwner{}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			if k8sOwner := plugin.ParseKubernetesTags(td.tags); k8sOwner != td.expectedK8sOwner {
+				t.Errorf("Parsing Kubernetes tags failed; expected %+v, received %+v", td.expectedK8sOwner, k8sOwner)
+			}
+		})
+	}
+}
+
+func TestParseEKSENIDescription(t *testing.T) {
+	var tests = []struct {
+		eniDesc, expectedClusterName string
+	}{
+		{"Amazon EKS prod", "prod"},
+		{"ELB app/my-alb/50dc6c495c0c9188", ""},
+		{"aws-K8S-i-0123456789abcdef0", ""},
+		{"", ""},
+	}
+
+	for _, td := range tests {
+		t.Run(td.eniDesc, func(t *testing.T) {
+			if clusterName := plugin.ParseEKSENIDescription(td.eniDesc); clusterName != td.expectedClusterName {
+				t.Errorf("Parsing EKS ENI description failed; expected %s, received %s", td.expectedClusterName, clusterName)
+			}
+		})
+	}
+}
+
+func TestAddKubernetesDetails(t *testing.T) {
+	var tests = []struct {
+		testName           string
+		k8sOwner           plugin.KubernetesOwner
+		expectedAttributes map[string]string
+	}{
+		{
+			"service",
+			plugin.KubernetesOwner{Cluster: "prod", Namespace: "web", Service: "frontend"},
+			map[string]string{"KubernetesCluster": "prod", "KubernetesNamespace": "web", "KubernetesService": "frontend"},
+		},
+		{
+			"node",
+			plugin.KubernetesOwner{Cluster: "prod", Nodegroup: "ng-general"},
+			map[string]string{"KubernetesCluster": "prod", "KubernetesNodegroup": "ng-general"},
+		},
+		{"none", plugin.KubernetesOwner{}, nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			var matchingResource generalResource.Resource
+
+			plugin.AddKubernetesDetails(&matchingResource, td.k8sOwner)
+
+			if !maps.Equal(matchingResource.Attributes, td.expectedAttributes) {
+				t.Errorf("Adding Kubernetes details failed; expected %v, received %v", td.expectedAttributes, matchingResource.Attributes)
+			}
+		})
+	}
+}
+
+func eksServerFactory() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/clusters/prod/node-groups":
+			_, _ = w.Write([]byte(`{"nodegroups": ["ng-general", "ng-gpu"]}`))
+		default:
+			// clusters that aren't managed by EKS (e.g. kops) aren't found
+			w.Header().Set("X-Amzn-Errortype", "ResourceNotFoundException")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "No cluster found"}`))
+		}
+	}))
+}
+
+func TestMapNetwork(t *testing.T) {
+	srv := eksServerFactory()
+	defer srv.Close()
+
+	eksp := plugin.EKSPlugin{AwsConn: awsconnector.AWSConnector{AwsConfig: aws.Config{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(srv.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIAEXAMPLE", "secret", ""),
+		Retryer:      func() aws.Retryer { return aws.NopRetryer{} },
+	}}}
+
+	var tests = []struct {
+		testName           string
+		k8sOwner           plugin.KubernetesOwner
+		expectedNetworkMap []string
+	}{
+		{"nodegroup", plugin.KubernetesOwner{Cluster: "prod", Nodegroup: "ng-general"}, []string{"eks:prod", "[ng-general]"}},
+		{"eksCluster", plugin.KubernetesOwner{Cluster: "prod"}, []string{"eks:prod", "[ng-general,ng-gpu]"}},
+		{"nonEKSCluster", plugin.KubernetesOwner{Cluster: "kops-staging"}, []string{"eks:kops-staging"}},
+		{"noCluster", plugin.KubernetesOwner{}, nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			var matchingResource generalResource.Resource
+
+			eksp.MapNetwork(&matchingResource, td.k8sOwner)
+
+			if !slices.Equal(matchingResource.NetworkMap, td.expectedNetworkMap) {
+				t.Errorf("Mapping EKS network failed; expected %v, received %v", td.expectedNetworkMap, matchingResource.NetworkMap)
+			}
+		})
+	}
+}
@@ -3,14 +3,18 @@ package plugin
 import (
 	"context"
 	"fmt"
-	"net"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	eksp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eks"
+	enip "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eni"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
@@ -18,6 +22,16 @@ import (
 type ELBPlugin struct {
 	AwsConn        awsconnector.AWSConnector
 	NetworkMapping bool
+	PrivateSearch  bool
+	VpcID          string
+}
+
+func init() {
+	registry.Register("aws", "elbv2", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return ELBPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping, PrivateSearch: awsCfg.PrivateSearch, VpcID: awsCfg.VpcID}
+	}, registry.PrivateSearch)
 }
 
 func (elbp ELBPlugin) GetElbListeners(elbArn string) ([]types.Listener, error) {
@@ -86,6 +100,23 @@ func AddElbAZDataToNetworkMap(matchingResource *generalResource.Resource, AZData
 	matchingResource.NetworkMap = append(matchingResource.NetworkMap, utils.FormatStrSliceAsCSV(AZDataSet))
 }
 
+func tagKeyValue(tag types.Tag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func (elbp ELBPlugin) GetTags(elbArn string) (map[string]string, error) {
+	elbClient := elasticloadbalancingv2.NewFromConfig(elbp.AwsConn.AwsConfig)
+
+	output, err := elbClient.DescribeTags(context.TODO(), &elasticloadbalancingv2.DescribeTagsInput{
+		ResourceArns: []string{elbArn},
+	})
+	if err != nil || len(output.TagDescriptions) == 0 {
+		return nil, err
+	}
+
+	return utils.FormatTags(output.TagDescriptions[0].Tags, tagKeyValue), nil
+}
+
 func (elbp ELBPlugin) GetResources() ([]types.LoadBalancer, error) {
 	var elbs []types.LoadBalancer
 
@@ -104,56 +135,96 @@ func (elbp ELBPlugin) GetResources() ([]types.LoadBalancer, error) {
 	return elbs, nil
 }
 
-func (elbp ELBPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
-	var elbIPAddrs []net.IP
-	var matchingResource generalResource.Resource
-	var elbListners []types.Listener
-	var elbTgts []ELBTarget
+func (elbp ELBPlugin) mapNetwork(elb types.LoadBalancer, matchingResource *generalResource.Resource) error {
+	matchingResource.NetworkMap = append(matchingResource.NetworkMap, *elb.DNSName, *elb.CanonicalHostedZoneId)
+
+	AddElbAZDataToNetworkMap(matchingResource, elb.AvailabilityZones)
+
+	elbListners, err := elbp.GetElbListeners(*elb.LoadBalancerArn)
+	if err != nil {
+		return err
+	}
+	elbTgts, err := elbp.GetElbTgts(elbListners)
+	if err != nil {
+		return err
+	}
+
+	var tgtSlug []string
+	for _, tgt := range elbTgts {
+		tgtSlug = append(tgtSlug, tgt.ListenerArn, tgt.TgtGrpArn)
+		tgtSlug = append(tgtSlug, tgt.TgtIds...)
+	}
+	matchingResource.NetworkMap = append(matchingResource.NetworkMap, tgtSlug...)
+
+	return nil
+}
+
+func (elbp ELBPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
 
 	elbResources, err := elbp.GetResources()
 	if err != nil {
-		return matchingResource, err
+		return matchingResources, err
+	}
+
+	// the IPs of each LB node are tied to an ENI that the ELB service manages, so there's no need to rely on DNS to find them
+	enip := enip.ENIPlugin{AwsConn: elbp.AwsConn, PrivateSearch: elbp.PrivateSearch, VpcID: elbp.VpcID}
+	elbIPAddrSet, err := enip.GetELBIPAddrs()
+	if err != nil {
+		return matchingResources, err
 	}
 
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
 	for _, elb := range elbResources {
-		elbIPAddrs, err = utils.LookupFQDN(*elb.DNSName)
-		if err != nil {
-			return matchingResource, err
-		}
+		// ENI descriptions reference the LB by the last portion of its ARN, e.g. app/my-alb/50dc6c495c0c9188
+		_, elbID, _ := strings.Cut(*elb.LoadBalancerArn, ":loadbalancer/")
+		elbIPAddrStrs := elbIPAddrSet[elbID]
+
+		for _, tgt := range ipMatcher.MatchAny(elbIPAddrStrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = elbID
+			matchingResource.RID = *elb.LoadBalancerArn
+			matchingResource.Name = aws.ToString(elb.LoadBalancerName)
+			matchingResource.CloudSvc = "elbv2"
+			if elb.State != nil {
+				matchingResource.Status = string(elb.State.Code)
+			}
 
-		for _, ipAddr := range elbIPAddrs {
-			if ipAddr.String() == tgtIP {
-				matchingResource.RID = *elb.LoadBalancerArn
-				matchingResource.CloudSvc = "elbv2"
-
-				if elbp.NetworkMapping {
-					matchingResource.NetworkMap = append(matchingResource.NetworkMap, *elb.DNSName, *elb.CanonicalHostedZoneId)
-
-					AddElbAZDataToNetworkMap(&matchingResource, elb.AvailabilityZones)
-
-					elbListners, err = elbp.GetElbListeners(*elb.LoadBalancerArn)
-					if err != nil {
-						return matchingResource, err
-					}
-					elbTgts, err = elbp.GetElbTgts(elbListners)
-					if err != nil {
-						return matchingResource, err
-					}
-
-					var tgtSlug []string
-					for _, tgt := range elbTgts {
-						tgtSlug = append(tgtSlug, tgt.ListenerArn, tgt.TgtGrpArn)
-						tgtSlug = append(tgtSlug, tgt.TgtIds...)
-					}
-					matchingResource.NetworkMap = append(matchingResource.NetworkMap, tgtSlug...)
-				}
+			matchingResource.AddAttribute("Scheme", string(elb.Scheme))
+			matchingResource.AddAttribute("Type", string(elb.Type))
+			matchingResource.AddAttribute("DNSName", aws.ToString(elb.DNSName))
 
-				log.Debug("IP found as Elastic Load Balancer -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			// LBs provisioned for Kubernetes services and ingresses are tagged with the cluster and object they belong to
+			matchingResource.Tags, err = elbp.GetTags(matchingResource.RID)
+			if err != nil {
+				return matchingResources, err
+			}
+			k8sOwner := eksp.ParseKubernetesTags(matchingResource.Tags)
+			eksp.AddKubernetesDetails(&matchingResource, k8sOwner)
+
+			for _, ipAddr := range elbIPAddrStrs {
+				matchingResource.AddIPAddr(ipAddr, elbp.PrivateSearch)
+			}
+
+			if elbp.NetworkMapping {
+				err = elbp.mapNetwork(elb, &matchingResource)
+				if err != nil {
+					return matchingResources, err
+				}
 
-				break
+				eksp.EKSPlugin{AwsConn: elbp.AwsConn}.MapNetwork(&matchingResource, k8sOwner)
 			}
+
+			log.Debug("IP ", tgt, " found as Elastic Load Balancer -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
 		}
 	}
 
-	return matchingResource, nil
+	return matchingResources, nil
+}
+
+func (elbp ELBPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(elbp, tgtIP)
 }
@@ -2,14 +2,17 @@ package plugin
 
 import (
 	"context"
-	"net"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
 	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing/types"
 
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	eksp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eks"
+	enip "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eni"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
@@ -17,6 +20,17 @@ import (
 type ELBv1Plugin struct {
 	AwsConn        awsconnector.AWSConnector
 	NetworkMapping bool
+	PrivateSearch  bool
+	VpcID          string
+}
+
+func init() {
+	// classic ELBs
+	registry.Register("aws", "elbv1", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return ELBv1Plugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping, PrivateSearch: awsCfg.PrivateSearch, VpcID: awsCfg.VpcID}
+	}, registry.PrivateSearch)
 }
 
 func (elbv1p ELBv1Plugin) GetResources() ([]types.LoadBalancerDescription, error) {
@@ -37,36 +51,82 @@ func (elbv1p ELBv1Plugin) GetResources() ([]types.LoadBalancerDescription, error
 	return elbs, nil
 }
 
-func (elbv1p ELBv1Plugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
-	var elbIPAddrs []net.IP
-	var matchingResource generalResource.Resource
+func classicTagKeyValue(tag types.Tag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func (elbv1p ELBv1Plugin) GetTags(elbName string) (map[string]string, error) {
+	elbClient := elasticloadbalancing.NewFromConfig(elbv1p.AwsConn.AwsConfig)
+
+	output, err := elbClient.DescribeTags(context.TODO(), &elasticloadbalancing.DescribeTagsInput{
+		LoadBalancerNames: []string{elbName},
+	})
+	if err != nil || len(output.TagDescriptions) == 0 {
+		return nil, err
+	}
+
+	return utils.FormatTags(output.TagDescriptions[0].Tags, classicTagKeyValue), nil
+}
+
+func (elbv1p ELBv1Plugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
 
 	elbResources, err := elbv1p.GetResources()
 	if err != nil {
-		return matchingResource, err
+		return matchingResources, err
 	}
 
+	// the IPs of each LB node are tied to an ENI that the ELB service manages, so there's no need to rely on DNS to find them
+	enip := enip.ENIPlugin{AwsConn: elbv1p.AwsConn, PrivateSearch: elbv1p.PrivateSearch, VpcID: elbv1p.VpcID}
+	elbIPAddrSet, err := enip.GetELBIPAddrs()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
 	for _, elb := range elbResources {
-		elbIPAddrs, err = utils.LookupFQDN(*elb.DNSName)
-		if err != nil {
-			return matchingResource, err
-		}
+		// classic ELB ENI descriptions only reference the LB's name
+		elbIPAddrStrs := elbIPAddrSet[*elb.LoadBalancerName]
 
-		for _, ipAddr := range elbIPAddrs {
-			if ipAddr.String() == tgtIP {
-				matchingResource.RID = *elb.LoadBalancerName
-				matchingResource.CloudSvc = "elbv1"
+		for _, tgt := range ipMatcher.MatchAny(elbIPAddrStrs) {
+			var matchingResource generalResource.Resource
 
-				if elbv1p.NetworkMapping {
-					matchingResource.NetworkMap = append(matchingResource.NetworkMap, *elb.DNSName, *elb.CanonicalHostedZoneNameID, *elb.VPCId, utils.FormatStrSliceAsCSV(elb.AvailabilityZones), utils.FormatStrSliceAsCSV(elb.Subnets))
-				}
+			matchingResource.Id = *elb.LoadBalancerName
+			matchingResource.RID = *elb.LoadBalancerName
+			matchingResource.Name = *elb.LoadBalancerName
+			matchingResource.CloudSvc = "elbv1"
 
-				log.Debug("IP found as Classic Elastic Load Balancer -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			// classic ELBs don't have a state, only a scheme
+			matchingResource.AddAttribute("Scheme", aws.ToString(elb.Scheme))
+			matchingResource.AddAttribute("DNSName", aws.ToString(elb.DNSName))
 
-				break
+			// the in-tree cloud provider creates classic ELBs for LoadBalancer services by default
+			matchingResource.Tags, err = elbv1p.GetTags(*elb.LoadBalancerName)
+			if err != nil {
+				return matchingResources, err
 			}
+			k8sOwner := eksp.ParseKubernetesTags(matchingResource.Tags)
+			eksp.AddKubernetesDetails(&matchingResource, k8sOwner)
+
+			for _, ipAddr := range elbIPAddrStrs {
+				matchingResource.AddIPAddr(ipAddr, elbv1p.PrivateSearch)
+			}
+
+			if elbv1p.NetworkMapping {
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, *elb.DNSName, *elb.CanonicalHostedZoneNameID, *elb.VPCId, utils.FormatStrSliceAsCSV(elb.AvailabilityZones), utils.FormatStrSliceAsCSV(elb.Subnets))
+
+				eksp.EKSPlugin{AwsConn: elbv1p.AwsConn}.MapNetwork(&matchingResource, k8sOwner)
+			}
+
+			log.Debug("IP ", tgt, " found as Classic Elastic Load Balancer -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
 		}
 	}
 
-	return matchingResource, nil
+	return matchingResources, nil
+}
+
+func (elbv1p ELBv1Plugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(elbv1p, tgtIP)
 }
@@ -0,0 +1,296 @@
+package plugin
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	ec2p "github.com/magneticstain/ip-2-cloudresource/aws/plugin/ec2"
+	eksp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eks"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// EC2 limits the number of values that can be set for a single filter
+const maxFilterValues = 200
+
+type ENIPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	NetworkMapping bool
+	PrivateSearch  bool
+	VpcID          string
+}
+
+func init() {
+	// searched as a fallback since most AWS public IPs live on an ENI, but the service-specific plugins know more about the resource that owns it
+	// private IPs are supported since every VPC resource with a private IP has an ENI, so owners are attributed the same way as for public IPs (e.g. VPC endpoints or Route 53 Resolver endpoints)
+	registry.Register("aws", "eni", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return ENIPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping, PrivateSearch: awsCfg.PrivateSearch, VpcID: awsCfg.VpcID}
+	}, registry.Fallback, registry.PrivateSearch)
+}
+
+func (enip ENIPlugin) GetResources(filters ...types.Filter) ([]types.NetworkInterface, error) {
+	var enis []types.NetworkInterface
+
+	ec2Client := ec2.NewFromConfig(enip.AwsConn.AwsConfig)
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(ec2Client, &ec2.DescribeNetworkInterfacesInput{
+		Filters: filters,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return enis, err
+		}
+
+		enis = append(enis, output.NetworkInterfaces...)
+	}
+
+	return enis, nil
+}
+
+func buildIPFilterSets(tgtIPs []string, ipv4FilterName string, scopeFilters ...types.Filter) [][]types.Filter {
+	// filters can't match on CIDR blocks, so every ENI (within scope) has to be fetched if any of the targets is one
+	var ipv4Addrs, ipv6Addrs []string
+	for _, tgtIP := range tgtIPs {
+		if strings.Contains(tgtIP, "/") {
+			if len(scopeFilters) == 0 {
+				return nil
+			}
+
+			return [][]types.Filter{scopeFilters}
+		}
+
+		// filter values have to match exactly, so the IP needs to be in the same format AWS uses
+		ipAddr, err := utils.NormalizeIPAddr(tgtIP)
+		if err != nil {
+			continue
+		}
+
+		if strings.Contains(ipAddr, ":") {
+			ipv6Addrs = append(ipv6Addrs, ipAddr)
+		} else {
+			ipv4Addrs = append(ipv4Addrs, ipAddr)
+		}
+	}
+
+	// separate filters are AND'd together, so each address family needs to be queried on its own
+	var filterSets [][]types.Filter
+	for _, ipFilter := range []struct {
+		name    string
+		ipAddrs []string
+	}{
+		{ipv4FilterName, ipv4Addrs},
+		{"ipv6-addresses.ipv6-address", ipv6Addrs},
+	} {
+		for ipAddrChunk := range slices.Chunk(ipFilter.ipAddrs, maxFilterValues) {
+			filterSets = append(filterSets, append([]types.Filter{{Name: aws.String(ipFilter.name), Values: ipAddrChunk}}, scopeFilters...))
+		}
+	}
+
+	return filterSets
+}
+
+func BuildIPFilterSets(tgtIPs []string) [][]types.Filter {
+	return buildIPFilterSets(tgtIPs, "association.public-ip")
+}
+
+// BuildPrivateIPFilterSets matches on every private IPv4 address of the ENI (not just the primary one), optionally scoped to a single VPC
+func BuildPrivateIPFilterSets(tgtIPs []string, vpcID string) [][]types.Filter {
+	var scopeFilters []types.Filter
+	if vpcID != "" {
+		scopeFilters = append(scopeFilters, types.Filter{Name: aws.String("vpc-id"), Values: []string{vpcID}})
+	}
+
+	return buildIPFilterSets(tgtIPs, "addresses.private-ip-address", scopeFilters...)
+}
+
+func GetPublicIPAddrs(eni types.NetworkInterface) []string {
+	var eniIPAddrs []string
+
+	if eni.Association != nil && eni.Association.PublicIp != nil {
+		eniIPAddrs = append(eniIPAddrs, *eni.Association.PublicIp)
+	}
+
+	for _, privateIPAddr := range eni.PrivateIpAddresses {
+		if privateIPAddr.Association != nil && privateIPAddr.Association.PublicIp != nil && !slices.Contains(eniIPAddrs, *privateIPAddr.Association.PublicIp) {
+			eniIPAddrs = append(eniIPAddrs, *privateIPAddr.Association.PublicIp)
+		}
+	}
+
+	for _, ipv6Addr := range eni.Ipv6Addresses {
+		if ipv6Addr.Ipv6Address != nil {
+			eniIPAddrs = append(eniIPAddrs, *ipv6Addr.Ipv6Address)
+		}
+	}
+
+	return eniIPAddrs
+}
+
+func GetPrivateIPAddrs(eni types.NetworkInterface) []string {
+	var eniIPAddrs []string
+
+	for _, privateIPAddr := range eni.PrivateIpAddresses {
+		if privateIPAddr.PrivateIpAddress != nil {
+			eniIPAddrs = append(eniIPAddrs, *privateIPAddr.PrivateIpAddress)
+		}
+	}
+
+	// IPv6 addresses are globally unique, but are still the address the ENI is reached at from within the VPC
+	for _, ipv6Addr := range eni.Ipv6Addresses {
+		if ipv6Addr.Ipv6Address != nil {
+			eniIPAddrs = append(eniIPAddrs, *ipv6Addr.Ipv6Address)
+		}
+	}
+
+	return eniIPAddrs
+}
+
+func (enip ENIPlugin) getIPAddrs(eni types.NetworkInterface) []string {
+	if enip.PrivateSearch {
+		return GetPrivateIPAddrs(eni)
+	}
+
+	return GetPublicIPAddrs(eni)
+}
+
+func (enip ENIPlugin) fetchENIs(tgtIPs []string) ([]types.NetworkInterface, error) {
+	filterSets := BuildIPFilterSets(tgtIPs)
+	if enip.PrivateSearch {
+		filterSets = BuildPrivateIPFilterSets(tgtIPs, enip.VpcID)
+	}
+	if filterSets == nil {
+		return enip.GetResources()
+	}
+
+	var enis []types.NetworkInterface
+	for _, filterSet := range filterSets {
+		filteredENIs, err := enip.GetResources(filterSet...)
+		if err != nil {
+			return enis, err
+		}
+
+		for _, eni := range filteredENIs {
+			// dual-stack ENIs can be returned by both the IPv4 and IPv6 queries
+			if !slices.ContainsFunc(enis, func(fetchedENI types.NetworkInterface) bool {
+				return *fetchedENI.NetworkInterfaceId == *eni.NetworkInterfaceId
+			}) {
+				enis = append(enis, eni)
+			}
+		}
+	}
+
+	return enis, nil
+}
+
+// GetELBIPAddrs maps the ID of each load balancer in the region (e.g. app/my-alb/50dc6c495c0c9188, or the name of a classic ELB) to the public IPs of its nodes, or their private IPs for private searches
+func (enip ENIPlugin) GetELBIPAddrs() (map[string][]string, error) {
+	elbIPAddrs := map[string][]string{}
+
+	filters := []types.Filter{{Name: aws.String("description"), Values: []string{"ELB *"}}}
+	if enip.VpcID != "" {
+		filters = append(filters, types.Filter{Name: aws.String("vpc-id"), Values: []string{enip.VpcID}})
+	}
+
+	enis, err := enip.GetResources(filters...)
+	if err != nil {
+		return elbIPAddrs, err
+	}
+
+	for _, eni := range enis {
+		elbID, err := ParseELBID(aws.ToString(eni.Description))
+		if err != nil {
+			continue
+		}
+
+		elbIPAddrs[elbID] = append(elbIPAddrs[elbID], enip.getIPAddrs(eni)...)
+	}
+
+	return elbIPAddrs, nil
+}
+
+// GetECSAttachmentENIs maps the ID of each ECS task ENI attachment in the region to the ENI that was created for it
+func (enip ENIPlugin) GetECSAttachmentENIs() (map[string]types.NetworkInterface, error) {
+	attachmentENIs := map[string]types.NetworkInterface{}
+
+	enis, err := enip.GetResources(types.Filter{Name: aws.String("description"), Values: []string{"arn:*:ecs:*:attachment/*"}})
+	if err != nil {
+		return attachmentENIs, err
+	}
+
+	for _, eni := range enis {
+		attachmentID, err := ParseECSAttachmentID(aws.ToString(eni.Description))
+		if err != nil {
+			continue
+		}
+
+		attachmentENIs[attachmentID] = eni
+	}
+
+	return attachmentENIs, nil
+}
+
+func (enip ENIPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	enis, err := enip.fetchENIs(tgtIPs)
+	if err != nil {
+		return matchingResources, err
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	for _, eni := range enis {
+		eniIPAddrs := enip.getIPAddrs(eni)
+
+		for _, tgt := range ipMatcher.MatchAny(eniIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			eniOwner := ResolveENIOwner(eni, enip.AwsConn.AwsConfig.Region)
+			matchingResource.Id = *eni.NetworkInterfaceId
+			matchingResource.RID = eniOwner.RID
+			matchingResource.CloudSvc = eniOwner.CloudSvc
+			matchingResource.Status = string(eni.Status)
+			matchingResource.Tags = utils.FormatTags(eni.TagSet, ec2p.TagKeyValue)
+			matchingResource.AddAttribute("InterfaceType", string(eni.InterfaceType))
+			matchingResource.AddAttribute("Description", aws.ToString(eni.Description))
+			matchingResource.AddAttribute("VpcId", aws.ToString(eni.VpcId))
+
+			// ENIs attached to EKS nodes are tagged by the VPC CNI, while the ones EKS manages itself only reference the cluster in their description
+			k8sOwner := eksp.ParseKubernetesTags(matchingResource.Tags)
+			if k8sOwner.Cluster == "" {
+				k8sOwner.Cluster = eksp.ParseEKSENIDescription(aws.ToString(eni.Description))
+			}
+			eksp.AddKubernetesDetails(&matchingResource, k8sOwner)
+
+			for _, ipAddr := range eniIPAddrs {
+				matchingResource.AddIPAddr(ipAddr, enip.PrivateSearch)
+			}
+
+			if enip.NetworkMapping {
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(eni.VpcId), aws.ToString(eni.SubnetId), *eni.NetworkInterfaceId)
+
+				eksp.EKSPlugin{AwsConn: enip.AwsConn}.MapNetwork(&matchingResource, k8sOwner)
+			}
+
+			log.Debug("IP ", tgt, " found as network interface ", matchingResource.Id, " owned by ", matchingResource.CloudSvc, " resource -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (enip ENIPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(enip, tgtIP)
+}
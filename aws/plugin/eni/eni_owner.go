@@ -0,0 +1,160 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+type ENIOwner struct {
+	CloudSvc, RID string
+}
+
+// maps the prefix of a requester-managed ENI's description to the service that owns it and the ARN resource type of the owner
+var eniDescriptionOwners = []struct {
+	descPrefix, cloudSvc, arnSvc, arnResourceType string
+}{
+	{"ELB app/", "elbv2", "elasticloadbalancing", "loadbalancer/"},
+	{"ELB net/", "elbv2", "elasticloadbalancing", "loadbalancer/"},
+	{"ELB gwy/", "elbv2", "elasticloadbalancing", "loadbalancer/"},
+	{"ELB ", "elbv1", "elasticloadbalancing", "loadbalancer/"},
+	{"Interface for NAT Gateway ", "natgw", "ec2", "natgateway/"},
+	{"VPC Endpoint Interface ", "vpce", "ec2", "vpc-endpoint/"},
+	{"Amazon EKS ", "eks", "eks", "cluster/"},
+	{"Network Interface for Transit Gateway Attachment ", "tgw", "ec2", "transit-gateway-attachment/"},
+}
+
+// maps the requester ID of a requester-managed ENI to the service that owns it, for when the description doesn't identify the owner
+var eniRequesterSvcs = map[string]string{
+	"amazon-elb":         "elbv2",
+	"amazon-rds":         "rds",
+	"amazon-redshift":    "redshift",
+	"amazon-elasticache": "elasticache",
+}
+
+func GetPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	default:
+		return "aws"
+	}
+}
+
+func buildARN(arnSvc, region, acctID, resource string) string {
+	return arn.ARN{
+		Partition: GetPartition(region),
+		Service:   arnSvc,
+		Region:    region,
+		AccountID: acctID,
+		Resource:  resource,
+	}.String()
+}
+
+// ResolveENIOwner uses the interface type, requester, and description of the ENI to determine which service and resource it belongs to; if the owner can't be determined, the ENI itself is reported as the owner
+func ResolveENIOwner(eni types.NetworkInterface, region string) ENIOwner {
+	acctID := aws.ToString(eni.OwnerId)
+	eniDesc := aws.ToString(eni.Description)
+
+	// ECS tasks using awsvpc networking use the task's ENI attachment ARN as the description
+	if eniARN, err := arn.Parse(eniDesc); err == nil && eniARN.Service == "ecs" {
+		return ENIOwner{CloudSvc: "ecs", RID: eniDesc}
+	}
+
+	for _, descOwner := range eniDescriptionOwners {
+		if resourceID, found := strings.CutPrefix(eniDesc, descOwner.descPrefix); found && resourceID != "" {
+			if descOwner.cloudSvc == "elbv2" {
+				// the description only has the name and ID, so the LB type needs to be added back in
+				resourceID = strings.TrimPrefix(descOwner.descPrefix, "ELB ") + resourceID
+			}
+
+			return ENIOwner{
+				CloudSvc: descOwner.cloudSvc,
+				RID:      buildARN(descOwner.arnSvc, region, acctID, descOwner.arnResourceType+resourceID),
+			}
+		}
+	}
+
+	// Route 53 Resolver endpoints create one ENI per IP, described as "Route 53 Resolver: <endpoint ID>:<resolver IP ID>"
+	if resolverDesc, found := strings.CutPrefix(eniDesc, "Route 53 Resolver: "); found {
+		if endpointID, _, _ := strings.Cut(resolverDesc, ":"); endpointID != "" {
+			return ENIOwner{CloudSvc: "route53resolver", RID: buildARN("route53resolver", region, acctID, "resolver-endpoint/"+endpointID)}
+		}
+	}
+
+	if fnDesc, found := strings.CutPrefix(eniDesc, "AWS Lambda VPC ENI-"); found {
+		// the function name is followed by a UUID, which is always 5 hyphen-delimited segments
+		descParts := strings.Split(fnDesc, "-")
+		if len(descParts) > 5 {
+			fnName := strings.Join(descParts[:len(descParts)-5], "-")
+
+			return ENIOwner{CloudSvc: "lambda", RID: buildARN("lambda", region, acctID, "function:"+fnName)}
+		}
+	}
+
+	if eni.Attachment != nil && eni.Attachment.InstanceId != nil && !aws.ToBool(eni.RequesterManaged) {
+		return ENIOwner{CloudSvc: "ec2", RID: buildARN("ec2", region, acctID, "instance/"+*eni.Attachment.InstanceId)}
+	}
+
+	eniOwner := ENIOwner{
+		CloudSvc: "eni",
+		RID:      buildARN("ec2", region, acctID, "network-interface/"+aws.ToString(eni.NetworkInterfaceId)),
+	}
+
+	switch eni.InterfaceType {
+	case types.NetworkInterfaceTypeNatGateway:
+		eniOwner.CloudSvc = "natgw"
+	case types.NetworkInterfaceTypeVpcEndpoint, types.NetworkInterfaceTypeGatewayLoadBalancerEndpoint:
+		eniOwner.CloudSvc = "vpce"
+	case types.NetworkInterfaceTypeLambda:
+		eniOwner.CloudSvc = "lambda"
+	case types.NetworkInterfaceTypeGlobalAcceleratorManaged:
+		eniOwner.CloudSvc = "globalaccelerator"
+	case types.NetworkInterfaceTypeApiGatewayManaged:
+		eniOwner.CloudSvc = "apigateway"
+	case types.NetworkInterfaceTypeTransitGateway:
+		eniOwner.CloudSvc = "tgw"
+	case types.NetworkInterfaceTypeQuicksight:
+		eniOwner.CloudSvc = "quicksight"
+	default:
+		if requesterSvc, found := eniRequesterSvcs[aws.ToString(eni.RequesterId)]; found {
+			eniOwner.CloudSvc = requesterSvc
+		} else if eniDesc == "RDSNetworkInterface" {
+			eniOwner.CloudSvc = "rds"
+		} else if strings.HasPrefix(eniDesc, "EFS mount target for ") {
+			eniOwner.CloudSvc = "efs"
+		}
+	}
+
+	return eniOwner
+}
+
+// ParseELBID extracts the load balancer ID (e.g. app/my-alb/50dc6c495c0c9188, or the name of a classic ELB) from the description of an ELB-managed ENI
+func ParseELBID(eniDesc string) (string, error) {
+	elbID, found := strings.CutPrefix(eniDesc, "ELB ")
+	if !found || elbID == "" {
+		return "", fmt.Errorf("'%s' is not the description of an ELB network interface", eniDesc)
+	}
+
+	return elbID, nil
+}
+
+// ParseECSAttachmentID extracts the ID of the task's ENI attachment from the description of an ENI used by an ECS task, e.g. arn:aws:ecs:us-east-1:123456789012:attachment/a1b2c3d4-5678-90ab-cdef-11111EXAMPLE
+func ParseECSAttachmentID(eniDesc string) (string, error) {
+	eniARN, err := arn.Parse(eniDesc)
+	if err != nil || eniARN.Service != "ecs" {
+		return "", fmt.Errorf("'%s' is not the description of an ECS task network interface", eniDesc)
+	}
+
+	attachmentID, found := strings.CutPrefix(eniARN.Resource, "attachment/")
+	if !found || attachmentID == "" {
+		return "", fmt.Errorf("'%s' is not the description of an ECS task network interface", eniDesc)
+	}
+
+	return attachmentID, nil
+}
@@ -0,0 +1,243 @@
+package plugin_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eni"
+)
+
+func enipFactory() plugin.ENIPlugin {
+	ac, _ := awsconnector.New()
+
+	enip := plugin.ENIPlugin{AwsConn: ac}
+
+	return enip
+}
+
+func TestGetResources(t *testing.T) {
+	enip := enipFactory()
+
+	eniResources, _ := enip.GetResources()
+
+	expectedType := "NetworkInterface"
+	for _, eni := range eniResources {
+		eniType := reflect.TypeOf(eni)
+		if eniType.Name() != expectedType {
+			t.Errorf("Fetching resources via ENI Plugin failed; wanted %s type, received %s", expectedType, eniType.Name())
+		}
+	}
+}
+
+func TestBuildIPFilterSets(t *testing.T) {
+	var tests = []struct {
+		testName            string
+		tgtIPs              []string
+		expectedFilterNames []string
+	}{
+		{"ipv4Only", []string{"1.1.1.1", "8.8.8.8"}, []string{"association.public-ip"}},
+		{"dualStack", []string{"1.1.1.1", "2600:1f18:0000::1"}, []string{"association.public-ip", "ipv6-addresses.ipv6-address"}},
+		{"cidrRequiresFullScan", []string{"1.1.1.1", "10.0.0.0/8"}, nil},
+		{"chunkedValues", slices.Repeat([]string{"1.1.1.1"}, 201), []string{"association.public-ip", "association.public-ip"}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			var filterNames []string
+			for _, filterSet := range plugin.BuildIPFilterSets(td.tgtIPs) {
+				filterNames = append(filterNames, *filterSet[0].Name)
+			}
+
+			if !slices.Equal(filterNames, td.expectedFilterNames) {
+				t.Errorf("Building ENI filters failed; expected %v, received %v", td.expectedFilterNames, filterNames)
+			}
+		})
+	}
+}
+
+func TestBuildPrivateIPFilterSets(t *testing.T) {
+	var tests = []struct {
+		testName            string
+		tgtIPs              []string
+		vpcID               string
+		expectedFilterNames [][]string
+	}{
+		{"ipv4Only", []string{"10.0.0.5", "10.0.0.6"}, "", [][]string{{"addresses.private-ip-address"}}},
+		{"vpcScoped", []string{"10.0.0.5", "2600:1f18:0000::1"}, "vpc-0123", [][]string{{"addresses.private-ip-address", "vpc-id"}, {"ipv6-addresses.ipv6-address", "vpc-id"}}},
+		{"cidrRequiresFullScan", []string{"10.0.0.0/8"}, "", nil},
+		{"cidrScopedToVpc", []string{"10.0.0.0/8"}, "vpc-0123", [][]string{{"vpc-id"}}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			var filterNames [][]string
+			for _, filterSet := range plugin.BuildPrivateIPFilterSets(td.tgtIPs, td.vpcID) {
+				var filterSetNames []string
+				for _, filter := range filterSet {
+					filterSetNames = append(filterSetNames, *filter.Name)
+				}
+
+				filterNames = append(filterNames, filterSetNames)
+			}
+
+			if !slices.EqualFunc(filterNames, td.expectedFilterNames, slices.Equal) {
+				t.Errorf("Building private ENI filters failed; expected %v, received %v", td.expectedFilterNames, filterNames)
+			}
+		})
+	}
+}
+
+func TestGetPublicIPAddrs(t *testing.T) {
+	eni := types.NetworkInterface{
+		Association: &types.NetworkInterfaceAssociation{PublicIp: aws.String("1.1.1.1")},
+		PrivateIpAddresses: []types.NetworkInterfacePrivateIpAddress{
+			{Association: &types.NetworkInterfaceAssociation{PublicIp: aws.String("1.1.1.1")}},
+			{Association: &types.NetworkInterfaceAssociation{PublicIp: aws.String("8.8.8.8")}},
+			{PrivateIpAddress: aws.String("10.0.0.5")},
+		},
+		Ipv6Addresses: []types.NetworkInterfaceIpv6Address{{Ipv6Address: aws.String("2600:1f18::1")}},
+	}
+
+	expectedIPAddrs := []string{"1.1.1.1", "8.8.8.8", "2600:1f18::1"}
+	ipAddrs := plugin.GetPublicIPAddrs(eni)
+	if !slices.Equal(ipAddrs, expectedIPAddrs) {
+		t.Errorf("Fetching public IPs from ENI failed; expected %v, received %v", expectedIPAddrs, ipAddrs)
+	}
+}
+
+func TestGetPrivateIPAddrs(t *testing.T) {
+	eni := types.NetworkInterface{
+		Association: &types.NetworkInterfaceAssociation{PublicIp: aws.String("1.1.1.1")},
+		PrivateIpAddresses: []types.NetworkInterfacePrivateIpAddress{
+			{PrivateIpAddress: aws.String("10.0.0.5"), Association: &types.NetworkInterfaceAssociation{PublicIp: aws.String("1.1.1.1")}},
+			{PrivateIpAddress: aws.String("10.0.0.6")},
+		},
+		Ipv6Addresses: []types.NetworkInterfaceIpv6Address{{Ipv6Address: aws.String("2600:1f18::1")}},
+	}
+
+	expectedIPAddrs := []string{"10.0.0.5", "10.0.0.6", "2600:1f18::1"}
+	ipAddrs := plugin.GetPrivateIPAddrs(eni)
+	if !slices.Equal(ipAddrs, expectedIPAddrs) {
+		t.Errorf("Fetching private IPs from ENI failed; expected %v, received %v", expectedIPAddrs, ipAddrs)
+	}
+}
+
+func TestResolveENIOwner(t *testing.T) {
+	var tests = []struct {
+		testName string
+		eni      types.NetworkInterface
+		expected plugin.ENIOwner
+	}{
+		{
+			"alb",
+			types.NetworkInterface{Description: aws.String("ELB app/my-alb/50dc6c495c0c9188"), RequesterId: aws.String("amazon-elb")},
+			plugin.ENIOwner{CloudSvc: "elbv2", RID: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/50dc6c495c0c9188"},
+		},
+		{
+			"classicElb",
+			types.NetworkInterface{Description: aws.String("ELB my-clb"), RequesterId: aws.String("amazon-elb")},
+			plugin.ENIOwner{CloudSvc: "elbv1", RID: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/my-clb"},
+		},
+		{
+			"natGateway",
+			types.NetworkInterface{Description: aws.String("Interface for NAT Gateway nat-0123456789abcdef0"), InterfaceType: types.NetworkInterfaceTypeNatGateway},
+			plugin.ENIOwner{CloudSvc: "natgw", RID: "arn:aws:ec2:us-east-1:123456789012:natgateway/nat-0123456789abcdef0"},
+		},
+		{
+			"lambda",
+			types.NetworkInterface{Description: aws.String("AWS Lambda VPC ENI-my-fn-1a2b3c4d-1111-2222-3333-444455556666"), InterfaceType: types.NetworkInterfaceTypeLambda},
+			plugin.ENIOwner{CloudSvc: "lambda", RID: "arn:aws:lambda:us-east-1:123456789012:function:my-fn"},
+		},
+		{
+			"ecsTask",
+			types.NetworkInterface{Description: aws.String("arn:aws:ecs:us-east-1:123456789012:attachment/1a2b3c4d-1111-2222-3333-444455556666")},
+			plugin.ENIOwner{CloudSvc: "ecs", RID: "arn:aws:ecs:us-east-1:123456789012:attachment/1a2b3c4d-1111-2222-3333-444455556666"},
+		},
+		{
+			"route53ResolverEndpoint",
+			types.NetworkInterface{Description: aws.String("Route 53 Resolver: rslvr-in-0123456789abcdef0:rni-0123456789abcdef0"), RequesterManaged: aws.Bool(true)},
+			plugin.ENIOwner{CloudSvc: "route53resolver", RID: "arn:aws:route53resolver:us-east-1:123456789012:resolver-endpoint/rslvr-in-0123456789abcdef0"},
+		},
+		{
+			"ec2Instance",
+			types.NetworkInterface{Attachment: &types.NetworkInterfaceAttachment{InstanceId: aws.String("i-0123456789abcdef0")}},
+			plugin.ENIOwner{CloudSvc: "ec2", RID: "arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0"},
+		},
+		{
+			"rds",
+			types.NetworkInterface{Description: aws.String("RDSNetworkInterface"), RequesterId: aws.String("amazon-rds"), RequesterManaged: aws.Bool(true)},
+			plugin.ENIOwner{CloudSvc: "rds", RID: "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0123456789abcdef0"},
+		},
+		{
+			"unknownOwner",
+			types.NetworkInterface{Description: aws.String("my custom ENI")},
+			plugin.ENIOwner{CloudSvc: "eni", RID: "arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0123456789abcdef0"},
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			td.eni.OwnerId = aws.String("123456789012")
+			td.eni.NetworkInterfaceId = aws.String("eni-0123456789abcdef0")
+
+			eniOwner := plugin.ResolveENIOwner(td.eni, "us-east-1")
+			if eniOwner != td.expected {
+				t.Errorf("Resolving ENI owner failed; expected %+v, received %+v", td.expected, eniOwner)
+			}
+		})
+	}
+}
+
+func TestParseECSAttachmentID(t *testing.T) {
+	var tests = []struct {
+		eniDesc, expectedAttachmentID string
+		expectErr                     bool
+	}{
+		{"arn:aws:ecs:us-east-1:123456789012:attachment/1a2b3c4d-1111-2222-3333-444455556666", "1a2b3c4d-1111-2222-3333-444455556666", false},
+		{"arn:aws-cn:ecs:cn-north-1:123456789012:attachment/1a2b3c4d-1111-2222-3333-444455556666", "1a2b3c4d-1111-2222-3333-444455556666", false},
+		{"arn:aws:ecs:us-east-1:123456789012:task/my-cluster/0123456789abcdef", "", true},
+		{"ELB app/my-alb/50dc6c495c0c9188", "", true},
+	}
+
+	for _, td := range tests {
+		t.Run(td.eniDesc, func(t *testing.T) {
+			attachmentID, err := plugin.ParseECSAttachmentID(td.eniDesc)
+			if (err != nil) != td.expectErr {
+				t.Errorf("Parsing ECS attachment ID failed; expected error: %t, received %v", td.expectErr, err)
+			}
+			if attachmentID != td.expectedAttachmentID {
+				t.Errorf("Parsing ECS attachment ID failed; expected %s, received %s", td.expectedAttachmentID, attachmentID)
+			}
+		})
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	enip := enipFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "Resource"},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			matchedENI, _ := enip.SearchResources(td.ipAddr)
+			matchedENIType := reflect.TypeOf(matchedENI)
+
+			if matchedENIType.Name() != td.expectedType {
+				t.Errorf("ENI search failed; expected %s after search, received %s", td.expectedType, matchedENIType.Name())
+			}
+		})
+	}
+}
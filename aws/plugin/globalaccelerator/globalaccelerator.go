@@ -0,0 +1,207 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/globalaccelerator"
+	"github.com/aws/aws-sdk-go-v2/service/globalaccelerator/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// Global Accelerator is a global service, but its API is only served out of us-west-2
+const controlPlaneRegion = "us-west-2"
+
+type GlobalAcceleratorPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	NetworkMapping bool
+}
+
+func init() {
+	// the same accelerators are returned no matter which region is searched
+	registry.Register("aws", "globalaccelerator", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return GlobalAcceleratorPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping}
+	}, registry.Global)
+}
+
+func (gap GlobalAcceleratorPlugin) newClient() *globalaccelerator.Client {
+	return globalaccelerator.NewFromConfig(gap.AwsConn.WithRegion(controlPlaneRegion).AwsConfig)
+}
+
+func GetAcceleratorIPAddrs(accelerator types.Accelerator) []string {
+	// dual-stack accelerators have an IPv4 and an IPv6 IP set, and BYOIP addresses are listed alongside Amazon-provided ones
+	var acceleratorIPAddrs []string
+	for _, ipSet := range accelerator.IpSets {
+		acceleratorIPAddrs = append(acceleratorIPAddrs, ipSet.IpAddresses...)
+	}
+
+	return acceleratorIPAddrs
+}
+
+// FormatListener summarizes the traffic accepted by the listener, e.g. TCP [80,8000-8080]
+func FormatListener(listener types.Listener) string {
+	var portRanges []string
+	for _, portRange := range listener.PortRanges {
+		fromPort, toPort := aws.ToInt32(portRange.FromPort), aws.ToInt32(portRange.ToPort)
+		if fromPort == toPort {
+			portRanges = append(portRanges, strconv.Itoa(int(fromPort)))
+		} else {
+			portRanges = append(portRanges, fmt.Sprintf("%d-%d", fromPort, toPort))
+		}
+	}
+
+	return fmt.Sprintf("%s %s", listener.Protocol, utils.FormatStrSliceAsCSV(portRanges))
+}
+
+// FormatEndpointGroup lists the endpoints (ALBs, NLBs, elastic IPs, or EC2 instances) that traffic is routed to within the group's region, e.g. us-east-1 [eipalloc-0123,i-0123]
+func FormatEndpointGroup(endpointGroup types.EndpointGroup) string {
+	var endpointIDs []string
+	for _, endpoint := range endpointGroup.EndpointDescriptions {
+		endpointIDs = append(endpointIDs, aws.ToString(endpoint.EndpointId))
+	}
+
+	return fmt.Sprintf("%s %s", aws.ToString(endpointGroup.EndpointGroupRegion), utils.FormatStrSliceAsCSV(endpointIDs))
+}
+
+func (gap GlobalAcceleratorPlugin) GetResources() ([]types.Accelerator, error) {
+	var accelerators []types.Accelerator
+
+	gaClient := gap.newClient()
+	paginator := globalaccelerator.NewListAcceleratorsPaginator(gaClient, &globalaccelerator.ListAcceleratorsInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return accelerators, err
+		}
+
+		accelerators = append(accelerators, output.Accelerators...)
+	}
+
+	return accelerators, nil
+}
+
+func tagKeyValue(tag types.Tag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func (gap GlobalAcceleratorPlugin) GetTags(acceleratorARN string) (map[string]string, error) {
+	gaClient := gap.newClient()
+
+	output, err := gaClient.ListTagsForResource(context.TODO(), &globalaccelerator.ListTagsForResourceInput{
+		ResourceArn: &acceleratorARN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.FormatTags(output.Tags, tagKeyValue), nil
+}
+
+func (gap GlobalAcceleratorPlugin) GetNetworkMap(acceleratorARN string) ([]string, error) {
+	// walk the accelerator's listeners down to the endpoints they route to, so it's clear what the anycast IPs actually front
+	var networkMap []string
+
+	gaClient := gap.newClient()
+	listenerPaginator := globalaccelerator.NewListListenersPaginator(gaClient, &globalaccelerator.ListListenersInput{
+		AcceleratorArn: &acceleratorARN,
+	})
+
+	for listenerPaginator.HasMorePages() {
+		listenerOutput, err := listenerPaginator.NextPage(context.TODO())
+		if err != nil {
+			return networkMap, err
+		}
+
+		for _, listener := range listenerOutput.Listeners {
+			networkMap = append(networkMap, FormatListener(listener))
+
+			endpointGroupPaginator := globalaccelerator.NewListEndpointGroupsPaginator(gaClient, &globalaccelerator.ListEndpointGroupsInput{
+				ListenerArn: listener.ListenerArn,
+			})
+
+			for endpointGroupPaginator.HasMorePages() {
+				endpointGroupOutput, err := endpointGroupPaginator.NextPage(context.TODO())
+				if err != nil {
+					return networkMap, err
+				}
+
+				for _, endpointGroup := range endpointGroupOutput.EndpointGroups {
+					networkMap = append(networkMap, FormatEndpointGroup(endpointGroup))
+				}
+			}
+		}
+	}
+
+	return networkMap, nil
+}
+
+func (gap GlobalAcceleratorPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	accelerators, err := gap.GetResources()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	for _, accelerator := range accelerators {
+		acceleratorIPAddrs := GetAcceleratorIPAddrs(accelerator)
+
+		for _, tgt := range ipMatcher.MatchAny(acceleratorIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.RID = aws.ToString(accelerator.AcceleratorArn)
+			// ARNs are formatted as arn:aws:globalaccelerator::<account ID>:accelerator/<accelerator ID>
+			matchingResource.Id = matchingResource.RID[strings.LastIndex(matchingResource.RID, "/")+1:]
+			matchingResource.Name = aws.ToString(accelerator.Name)
+			matchingResource.CloudSvc = "globalaccelerator"
+			matchingResource.Status = string(accelerator.Status)
+
+			matchingResource.Tags, err = gap.GetTags(matchingResource.RID)
+			if err != nil {
+				return matchingResources, err
+			}
+
+			matchingResource.AddAttribute("DnsName", aws.ToString(accelerator.DnsName))
+			matchingResource.AddAttribute("DualStackDnsName", aws.ToString(accelerator.DualStackDnsName))
+			matchingResource.AddAttribute("Enabled", strconv.FormatBool(aws.ToBool(accelerator.Enabled)))
+			matchingResource.AddAttribute("IpAddressType", string(accelerator.IpAddressType))
+
+			for _, ipAddr := range acceleratorIPAddrs {
+				matchingResource.AddPublicIPAddr(ipAddr)
+			}
+
+			if gap.NetworkMapping {
+				acceleratorNetworkMap, err := gap.GetNetworkMap(matchingResource.RID)
+				if err != nil {
+					return matchingResources, err
+				}
+
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(accelerator.DnsName))
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, acceleratorNetworkMap...)
+			}
+
+			log.Debug("IP ", tgt, " found as Global Accelerator -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (gap GlobalAcceleratorPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(gap, tgtIP)
+}
@@ -0,0 +1,114 @@
+package plugin_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/globalaccelerator/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/globalaccelerator"
+)
+
+func gapFactory() plugin.GlobalAcceleratorPlugin {
+	ac, _ := awsconnector.New()
+
+	gap := plugin.GlobalAcceleratorPlugin{AwsConn: ac}
+
+	return gap
+}
+
+func TestGetResources(t *testing.T) {
+	gap := gapFactory()
+
+	gaResources, _ := gap.GetResources()
+
+	expectedType := "Accelerator"
+	for _, accelerator := range gaResources {
+		acceleratorType := reflect.TypeOf(accelerator)
+		if acceleratorType.Name() != expectedType {
+			t.Errorf("Fetching resources via Global Accelerator Plugin failed; wanted %s type, received %s", expectedType, acceleratorType.Name())
+		}
+	}
+}
+
+func TestGetAcceleratorIPAddrs(t *testing.T) {
+	accelerator := types.Accelerator{
+		IpSets: []types.IpSet{
+			{IpAddressFamily: types.IpAddressFamilyIPv4, IpAddresses: []string{"75.2.0.1", "99.83.0.1"}},
+			{IpAddressFamily: types.IpAddressFamilyIPv6, IpAddresses: []string{"2600:9000:a000::1", "2600:9000:a100::1"}},
+		},
+	}
+
+	expectedIPAddrs := []string{"75.2.0.1", "99.83.0.1", "2600:9000:a000::1", "2600:9000:a100::1"}
+	if acceleratorIPAddrs := plugin.GetAcceleratorIPAddrs(accelerator); !slices.Equal(acceleratorIPAddrs, expectedIPAddrs) {
+		t.Errorf("Fetching Global Accelerator IPs failed; expected %v, received %v", expectedIPAddrs, acceleratorIPAddrs)
+	}
+}
+
+func TestFormatListener(t *testing.T) {
+	listener := types.Listener{
+		Protocol: types.ProtocolTcp,
+		PortRanges: []types.PortRange{
+			{FromPort: aws.Int32(443), ToPort: aws.Int32(443)},
+			{FromPort: aws.Int32(8000), ToPort: aws.Int32(8080)},
+		},
+	}
+
+	expectedListener := "TCP [443,8000-8080]"
+	if formattedListener := plugin.FormatListener(listener); formattedListener != expectedListener {
+		t.Errorf("Formatting Global Accelerator listener failed; expected %s, received %s", expectedListener, formattedListener)
+	}
+}
+
+func TestFormatEndpointGroup(t *testing.T) {
+	var tests = []struct {
+		name, expectedEndpointGroup string
+		endpointGroup               types.EndpointGroup
+	}{
+		{
+			"multipleEndpoints",
+			"us-east-1 [arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/0123,eipalloc-0123]",
+			types.EndpointGroup{
+				EndpointGroupRegion: aws.String("us-east-1"),
+				EndpointDescriptions: []types.EndpointDescription{
+					{EndpointId: aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/web/0123")},
+					{EndpointId: aws.String("eipalloc-0123")},
+				},
+			},
+		},
+		{"noEndpoints", "eu-west-1 []", types.EndpointGroup{EndpointGroupRegion: aws.String("eu-west-1")}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			if formattedEndpointGroup := plugin.FormatEndpointGroup(td.endpointGroup); formattedEndpointGroup != td.expectedEndpointGroup {
+				t.Errorf("Formatting Global Accelerator endpoint group failed; expected %s, received %s", td.expectedEndpointGroup, formattedEndpointGroup)
+			}
+		})
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	gap := gapFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.ipAddr, func(t *testing.T) {
+			matchedAccelerator, _ := gap.SearchResources(td.ipAddr)
+			matchedAcceleratorType := reflect.TypeOf(matchedAccelerator)
+
+			if matchedAcceleratorType.Name() != td.expectedType {
+				t.Errorf("Global Accelerator search failed; expected %s after search, received %s", td.expectedType, matchedAcceleratorType.Name())
+			}
+		})
+	}
+}
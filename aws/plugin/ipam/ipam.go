@@ -0,0 +1,390 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	enip "github.com/magneticstain/ip-2-cloudresource/aws/plugin/eni"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// IPAMPlugin resolves IPs using the inventory of a VPC IPAM, which already covers every account and region the IPAM monitors; it isn't registered as a service plugin since it searches across services
+type IPAMPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	IpamID         string
+	NetworkMapping bool
+	PrivateSearch  bool
+	VpcID          string
+	// limits the search to these regions, if set
+	Regions []string
+}
+
+// maps the AWS service that IPAM reports as using a public IP to the service plugin that can search it, for when the owner can't be determined from the ENI
+var ipamSvcs = map[types.IpamPublicAddressAwsService]string{
+	types.IpamPublicAddressAwsServiceNatGateway: "natgw",
+	types.IpamPublicAddressAwsServiceDms:        "dms",
+	types.IpamPublicAddressAwsServiceRedshift:   "redshift",
+	types.IpamPublicAddressAwsServiceEcs:        "ecs",
+	types.IpamPublicAddressAwsServiceRds:        "rds",
+	types.IpamPublicAddressAwsServiceS2sVpn:     "vpn",
+	types.IpamPublicAddressAwsServiceEc2Lb:      "elbv2",
+	types.IpamPublicAddressAwsServiceAga:        "globalaccelerator",
+	types.IpamPublicAddressAwsServiceCloudfront: "cloudfront",
+}
+
+func resourceTagKeyValue(tag types.IpamResourceTag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func addressTagKeyValue(tag types.IpamPublicAddressTag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func (ipamp IPAMPlugin) GetIpam() (types.Ipam, error) {
+	ec2Client := ec2.NewFromConfig(ipamp.AwsConn.AwsConfig)
+
+	output, err := ec2Client.DescribeIpams(context.TODO(), &ec2.DescribeIpamsInput{
+		IpamIds: []string{ipamp.IpamID},
+	})
+	if err != nil {
+		return types.Ipam{}, err
+	}
+
+	if len(output.Ipams) == 0 {
+		// IPAMs are only visible from their home region
+		return types.Ipam{}, fmt.Errorf("IPAM %s not found in %s; the IPAM's home region must be used as the default region", ipamp.IpamID, ipamp.AwsConn.AwsConfig.Region)
+	}
+
+	return output.Ipams[0], nil
+}
+
+// GetOperatingRegions returns the regions monitored by the IPAM, limited to the given regions if any are provided
+func GetOperatingRegions(ipam types.Ipam, regions []string) []string {
+	var operatingRegions []string
+
+	for _, operatingRegion := range ipam.OperatingRegions {
+		regionName := aws.ToString(operatingRegion.RegionName)
+		if len(regions) == 0 || slices.Contains(regions, regionName) {
+			operatingRegions = append(operatingRegions, regionName)
+		}
+	}
+
+	return operatingRegions
+}
+
+func (ipamp IPAMPlugin) GetDiscoveredPublicAddresses(resourceDiscoveryID, region string) ([]types.IpamDiscoveredPublicAddress, error) {
+	var publicAddrs []types.IpamDiscoveredPublicAddress
+
+	ec2Client := ec2.NewFromConfig(ipamp.AwsConn.AwsConfig)
+
+	// GetIpamDiscoveredPublicAddresses doesn't have a paginator, so the pages are walked manually
+	input := &ec2.GetIpamDiscoveredPublicAddressesInput{
+		IpamResourceDiscoveryId: &resourceDiscoveryID,
+		AddressRegion:           &region,
+	}
+	for {
+		output, err := ec2Client.GetIpamDiscoveredPublicAddresses(context.TODO(), input)
+		if err != nil {
+			return publicAddrs, err
+		}
+
+		publicAddrs = append(publicAddrs, output.IpamDiscoveredPublicAddresses...)
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return publicAddrs, nil
+}
+
+func (ipamp IPAMPlugin) GetResourceCidrs(scopeID string) ([]types.IpamResourceCidr, error) {
+	var resourceCidrs []types.IpamResourceCidr
+
+	ec2Client := ec2.NewFromConfig(ipamp.AwsConn.AwsConfig)
+	paginator := ec2.NewGetIpamResourceCidrsPaginator(ec2Client, &ec2.GetIpamResourceCidrsInput{
+		IpamScopeId: &scopeID,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return resourceCidrs, err
+		}
+
+		for _, resourceCidr := range output.IpamResourceCidrs {
+			if len(ipamp.Regions) == 0 || slices.Contains(ipamp.Regions, aws.ToString(resourceCidr.ResourceRegion)) {
+				resourceCidrs = append(resourceCidrs, resourceCidr)
+			}
+		}
+	}
+
+	return resourceCidrs, nil
+}
+
+// ResolveAddressOwner determines which service and resource is using a public IP discovered by IPAM, reusing the ENI owner logic when the IP is attached to an ENI
+func ResolveAddressOwner(publicAddr types.IpamDiscoveredPublicAddress) enip.ENIOwner {
+	region := aws.ToString(publicAddr.AddressRegion)
+	ipamSvc, ipamSvcFound := ipamSvcs[publicAddr.Service]
+
+	if publicAddr.NetworkInterfaceId != nil {
+		eni := types.NetworkInterface{
+			NetworkInterfaceId: publicAddr.NetworkInterfaceId,
+			Description:        publicAddr.NetworkInterfaceDescription,
+			OwnerId:            publicAddr.AddressOwnerId,
+		}
+		if publicAddr.InstanceId != nil {
+			eni.Attachment = &types.NetworkInterfaceAttachment{InstanceId: publicAddr.InstanceId}
+		}
+
+		eniOwner := enip.ResolveENIOwner(eni, region)
+		if eniOwner.CloudSvc == "eni" && ipamSvcFound {
+			eniOwner.CloudSvc = ipamSvc
+		}
+
+		return eniOwner
+	}
+
+	if ipamSvcFound {
+		return generalOwner(ipamSvc, publicAddr)
+	}
+
+	if publicAddr.AddressAllocationId != nil {
+		// elastic IPs that aren't associated with anything
+		return enip.ENIOwner{CloudSvc: "eip", RID: aws.ToString(publicAddr.AddressAllocationId)}
+	}
+
+	return generalOwner("ipam", publicAddr)
+}
+
+func generalOwner(cloudSvc string, publicAddr types.IpamDiscoveredPublicAddress) enip.ENIOwner {
+	rid := aws.ToString(publicAddr.ServiceResource)
+	if rid == "" {
+		rid = aws.ToString(publicAddr.Address)
+	}
+
+	return enip.ENIOwner{CloudSvc: cloudSvc, RID: rid}
+}
+
+func AddDiscoveredAddressDetails(matchingResource *generalResource.Resource, publicAddr types.IpamDiscoveredPublicAddress) {
+	owner := ResolveAddressOwner(publicAddr)
+
+	matchingResource.Id = aws.ToString(publicAddr.InstanceId)
+	if matchingResource.Id == "" {
+		matchingResource.Id = owner.RID
+	}
+	matchingResource.RID = owner.RID
+	matchingResource.CloudSvc = owner.CloudSvc
+	matchingResource.AccountID = aws.ToString(publicAddr.AddressOwnerId)
+	matchingResource.Region = aws.ToString(publicAddr.AddressRegion)
+	matchingResource.Status = string(publicAddr.AssociationStatus)
+	if publicAddr.Tags != nil {
+		matchingResource.Tags = utils.FormatTags(publicAddr.Tags.EipTags, addressTagKeyValue)
+	}
+	matchingResource.Name = matchingResource.Tags["Name"]
+	matchingResource.AddPublicIPAddr(aws.ToString(publicAddr.Address))
+
+	matchingResource.AddAttribute("Source", "ipam")
+	matchingResource.AddAttribute("AddressType", string(publicAddr.AddressType))
+	matchingResource.AddAttribute("Service", string(publicAddr.Service))
+	matchingResource.AddAttribute("ServiceResource", aws.ToString(publicAddr.ServiceResource))
+	matchingResource.AddAttribute("AllocationId", aws.ToString(publicAddr.AddressAllocationId))
+	matchingResource.AddAttribute("InstanceId", aws.ToString(publicAddr.InstanceId))
+	matchingResource.AddAttribute("NetworkInterfaceId", aws.ToString(publicAddr.NetworkInterfaceId))
+	matchingResource.AddAttribute("PublicIpv4Pool", aws.ToString(publicAddr.PublicIpv4PoolId))
+	matchingResource.AddAttribute("VpcId", aws.ToString(publicAddr.VpcId))
+	matchingResource.AddAttribute("SubnetId", aws.ToString(publicAddr.SubnetId))
+}
+
+func AddResourceCidrDetails(matchingResource *generalResource.Resource, resourceCidr types.IpamResourceCidr) {
+	matchingResource.Id = aws.ToString(resourceCidr.ResourceId)
+	matchingResource.RID = matchingResource.Id
+	matchingResource.Name = aws.ToString(resourceCidr.ResourceName)
+	matchingResource.AccountID = aws.ToString(resourceCidr.ResourceOwnerId)
+	matchingResource.Region = aws.ToString(resourceCidr.ResourceRegion)
+	matchingResource.Tags = utils.FormatTags(resourceCidr.ResourceTags, resourceTagKeyValue)
+
+	switch resourceCidr.ResourceType {
+	case types.IpamResourceTypeEip:
+		matchingResource.CloudSvc = "eip"
+	case types.IpamResourceTypeEni:
+		matchingResource.CloudSvc = "eni"
+	case types.IpamResourceTypeVpc, types.IpamResourceTypeSubnet:
+		// only the network the IP belongs to is known, not the resource using it
+		matchingResource.CloudSvc = "vpc"
+	default:
+		matchingResource.CloudSvc = "ipam"
+	}
+
+	matchingResource.AddAttribute("Source", "ipam")
+	matchingResource.AddAttribute("ResourceType", string(resourceCidr.ResourceType))
+	matchingResource.AddAttribute("ResourceCidr", aws.ToString(resourceCidr.ResourceCidr))
+	matchingResource.AddAttribute("IpamPoolId", aws.ToString(resourceCidr.IpamPoolId))
+	matchingResource.AddAttribute("VpcId", aws.ToString(resourceCidr.VpcId))
+}
+
+func getCidrVpcID(resourceCidr types.IpamResourceCidr) string {
+	if resourceCidr.ResourceType == types.IpamResourceTypeVpc {
+		return aws.ToString(resourceCidr.ResourceId)
+	}
+
+	return aws.ToString(resourceCidr.VpcId)
+}
+
+// GetMostSpecificCidrs drops every CIDR that contains another one in the set, e.g. a VPC whose subnet also matched, so only the narrowest networks are reported
+func GetMostSpecificCidrs(resourceCidrs []types.IpamResourceCidr) []types.IpamResourceCidr {
+	var mostSpecificCidrs []types.IpamResourceCidr
+
+	for i, resourceCidr := range resourceCidrs {
+		prefix, err := netip.ParsePrefix(aws.ToString(resourceCidr.ResourceCidr))
+		if err != nil {
+			continue
+		}
+
+		containsOther := slices.ContainsFunc(resourceCidrs, func(otherCidr types.IpamResourceCidr) bool {
+			otherPrefix, err := netip.ParsePrefix(aws.ToString(otherCidr.ResourceCidr))
+			if err != nil || otherPrefix.Bits() <= prefix.Bits() || !prefix.Contains(otherPrefix.Addr()) {
+				return false
+			}
+
+			// private ranges overlap across VPCs, so a subnet only narrows down the VPC it belongs to
+			vpcID, otherVpcID := getCidrVpcID(resourceCidr), getCidrVpcID(otherCidr)
+
+			return vpcID == "" || otherVpcID == "" || vpcID == otherVpcID
+		})
+		if containsOther {
+			continue
+		}
+
+		// the same CIDR can be reported by both the resource and its pool allocation
+		if slices.ContainsFunc(resourceCidrs[:i], func(otherCidr types.IpamResourceCidr) bool {
+			return aws.ToString(otherCidr.ResourceCidr) == aws.ToString(resourceCidr.ResourceCidr) && aws.ToString(otherCidr.ResourceId) == aws.ToString(resourceCidr.ResourceId)
+		}) {
+			continue
+		}
+
+		mostSpecificCidrs = append(mostSpecificCidrs, resourceCidr)
+	}
+
+	return mostSpecificCidrs
+}
+
+func (ipamp IPAMPlugin) searchDiscoveredPublicAddresses(ipam types.Ipam, ipMatcher utils.IPMatcher, matchingResources map[string][]generalResource.Resource) error {
+	for _, region := range GetOperatingRegions(ipam, ipamp.Regions) {
+		publicAddrs, err := ipamp.GetDiscoveredPublicAddresses(aws.ToString(ipam.DefaultResourceDiscoveryId), region)
+		if err != nil {
+			return fmt.Errorf("unable to fetch public IPs discovered by IPAM in %s: %w", region, err)
+		}
+
+		for _, publicAddr := range publicAddrs {
+			for _, tgt := range ipMatcher.Match(aws.ToString(publicAddr.Address)) {
+				var matchingResource generalResource.Resource
+
+				AddDiscoveredAddressDetails(&matchingResource, publicAddr)
+
+				if ipamp.NetworkMapping {
+					for _, networkResource := range []*string{publicAddr.VpcId, publicAddr.SubnetId, publicAddr.NetworkInterfaceId, publicAddr.InstanceId} {
+						if networkResource != nil {
+							matchingResource.NetworkMap = append(matchingResource.NetworkMap, *networkResource)
+						}
+					}
+				}
+
+				log.Debug("IP ", tgt, " found in IPAM as ", matchingResource.CloudSvc, " resource -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+				matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ipamp IPAMPlugin) searchResourceCidrs(scopeID string, ipMatcher utils.IPMatcher, matchingResources map[string][]generalResource.Resource) error {
+	resourceCidrs, err := ipamp.GetResourceCidrs(scopeID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch resource CIDRs from IPAM scope %s: %w", scopeID, err)
+	}
+
+	tgtCidrs := map[string][]types.IpamResourceCidr{}
+	for _, resourceCidr := range resourceCidrs {
+		if ipamp.VpcID != "" && aws.ToString(resourceCidr.VpcId) != ipamp.VpcID {
+			continue
+		}
+
+		for _, tgt := range ipMatcher.MatchPrefix(aws.ToString(resourceCidr.ResourceCidr)) {
+			// IPs already attributed to a resource don't need to be narrowed down to a network
+			if len(matchingResources[tgt]) == 0 {
+				tgtCidrs[tgt] = append(tgtCidrs[tgt], resourceCidr)
+			}
+		}
+	}
+
+	for tgt, cidrs := range tgtCidrs {
+		for _, resourceCidr := range GetMostSpecificCidrs(cidrs) {
+			var matchingResource generalResource.Resource
+
+			AddResourceCidrDetails(&matchingResource, resourceCidr)
+
+			if ipamp.NetworkMapping {
+				vpcID := aws.ToString(resourceCidr.VpcId)
+				if vpcID != "" && vpcID != matchingResource.Id {
+					matchingResource.NetworkMap = append(matchingResource.NetworkMap, vpcID)
+				}
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, matchingResource.Id)
+			}
+
+			log.Debug("IP ", tgt, " found in IPAM within ", resourceCidr.ResourceType, " ", matchingResource.RID, " (", aws.ToString(resourceCidr.ResourceCidr), ")")
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	return nil
+}
+
+// SearchResourcesBulk looks the IPs up in IPAM; public IPs are matched against the public addresses IPAM has discovered first, and any IP that isn't found is narrowed down to the most specific resource CIDR that contains it
+func (ipamp IPAMPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	ipam, err := ipamp.GetIpam()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	// IPAM API calls are all made from the IPAM's home region, even for resources in other regions
+	ipamp.AwsConn = ipamp.AwsConn.WithRegion(aws.ToString(ipam.IpamRegion))
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	scopeID := aws.ToString(ipam.PrivateDefaultScopeId)
+	if !ipamp.PrivateSearch {
+		scopeID = aws.ToString(ipam.PublicDefaultScopeId)
+
+		err = ipamp.searchDiscoveredPublicAddresses(ipam, ipMatcher, matchingResources)
+		if err != nil {
+			return matchingResources, err
+		}
+	}
+
+	err = ipamp.searchResourceCidrs(scopeID, ipMatcher, matchingResources)
+	if err != nil {
+		return matchingResources, err
+	}
+
+	return matchingResources, nil
+}
+
+func (ipamp IPAMPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(ipamp, tgtIP)
+}
@@ -0,0 +1,200 @@
+package plugin_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/ipam"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func TestGetOperatingRegions(t *testing.T) {
+	ipam := types.Ipam{
+		OperatingRegions: []types.IpamOperatingRegion{
+			{RegionName: aws.String("us-east-1")},
+			{RegionName: aws.String("us-west-2")},
+			{RegionName: aws.String("eu-west-1")},
+		},
+	}
+
+	var tests = []struct {
+		testName                 string
+		regions, expectedRegions []string
+	}{
+		{"allRegions", nil, []string{"us-east-1", "us-west-2", "eu-west-1"}},
+		{"limitedRegions", []string{"eu-west-1", "ap-south-1"}, []string{"eu-west-1"}},
+		{"noOverlap", []string{"ap-south-1"}, nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			if regions := plugin.GetOperatingRegions(ipam, td.regions); !slices.Equal(regions, td.expectedRegions) {
+				t.Errorf("Fetching IPAM operating regions failed; expected %v, received %v", td.expectedRegions, regions)
+			}
+		})
+	}
+}
+
+func TestResolveAddressOwner(t *testing.T) {
+	var tests = []struct {
+		testName                      string
+		publicAddr                    types.IpamDiscoveredPublicAddress
+		expectedCloudSvc, expectedRID string
+	}{
+		{
+			"ec2Instance",
+			types.IpamDiscoveredPublicAddress{
+				Address:            aws.String("203.0.113.10"),
+				AddressOwnerId:     aws.String("123456789012"),
+				AddressRegion:      aws.String("us-east-1"),
+				InstanceId:         aws.String("i-0123456789abcdef0"),
+				NetworkInterfaceId: aws.String("eni-0123456789abcdef0"),
+				Service:            types.IpamPublicAddressAwsServiceOther,
+			},
+			"ec2",
+			"arn:aws:ec2:us-east-1:123456789012:instance/i-0123456789abcdef0",
+		},
+		{
+			"applicationLoadBalancer",
+			types.IpamDiscoveredPublicAddress{
+				Address:                     aws.String("203.0.113.11"),
+				AddressOwnerId:              aws.String("123456789012"),
+				AddressRegion:               aws.String("us-east-1"),
+				NetworkInterfaceId:          aws.String("eni-0123456789abcdef1"),
+				NetworkInterfaceDescription: aws.String("ELB app/my-alb/50dc6c495c0c9188"),
+				Service:                     types.IpamPublicAddressAwsServiceEc2Lb,
+			},
+			"elbv2",
+			"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-alb/50dc6c495c0c9188",
+		},
+		{
+			"rdsInstance",
+			types.IpamDiscoveredPublicAddress{
+				Address:            aws.String("203.0.113.12"),
+				AddressOwnerId:     aws.String("123456789012"),
+				AddressRegion:      aws.String("us-east-1"),
+				NetworkInterfaceId: aws.String("eni-0123456789abcdef2"),
+				Service:            types.IpamPublicAddressAwsServiceRds,
+			},
+			"rds",
+			"arn:aws:ec2:us-east-1:123456789012:network-interface/eni-0123456789abcdef2",
+		},
+		{
+			"unassociatedEIP",
+			types.IpamDiscoveredPublicAddress{
+				Address:             aws.String("203.0.113.13"),
+				AddressAllocationId: aws.String("eipalloc-0123456789abcdef0"),
+				AddressRegion:       aws.String("us-east-1"),
+				Service:             types.IpamPublicAddressAwsServiceOther,
+			},
+			"eip",
+			"eipalloc-0123456789abcdef0",
+		},
+		{
+			"globalAccelerator",
+			types.IpamDiscoveredPublicAddress{
+				Address:         aws.String("203.0.113.14"),
+				AddressRegion:   aws.String("us-west-2"),
+				Service:         types.IpamPublicAddressAwsServiceAga,
+				ServiceResource: aws.String("arn:aws:globalaccelerator::123456789012:accelerator/1234abcd-abcd-1234-abcd-1234abcdefgh"),
+			},
+			"globalaccelerator",
+			"arn:aws:globalaccelerator::123456789012:accelerator/1234abcd-abcd-1234-abcd-1234abcdefgh",
+		},
+		{
+			"unknown",
+			types.IpamDiscoveredPublicAddress{
+				Address:       aws.String("203.0.113.15"),
+				AddressRegion: aws.String("us-east-1"),
+				Service:       types.IpamPublicAddressAwsServiceOther,
+			},
+			"ipam",
+			"203.0.113.15",
+		},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			owner := plugin.ResolveAddressOwner(td.publicAddr)
+			if owner.CloudSvc != td.expectedCloudSvc || owner.RID != td.expectedRID {
+				t.Errorf("Resolving owner of IPAM public address failed; expected %s (%s), received %s (%s)", td.expectedCloudSvc, td.expectedRID, owner.CloudSvc, owner.RID)
+			}
+		})
+	}
+}
+
+func TestGetMostSpecificCidrs(t *testing.T) {
+	resourceCidrs := []types.IpamResourceCidr{
+		{ResourceId: aws.String("vpc-0123456789abcdef0"), ResourceCidr: aws.String("10.0.0.0/16"), ResourceType: types.IpamResourceTypeVpc},
+		{ResourceId: aws.String("subnet-0123456789abcdef0"), ResourceCidr: aws.String("10.0.1.0/24"), ResourceType: types.IpamResourceTypeSubnet, VpcId: aws.String("vpc-0123456789abcdef0")},
+		{ResourceId: aws.String("subnet-0123456789abcdef0"), ResourceCidr: aws.String("10.0.1.0/24"), ResourceType: types.IpamResourceTypeSubnet, VpcId: aws.String("vpc-0123456789abcdef0")},
+		{ResourceId: aws.String("ipv4pool-ec2-0123456789abcdef0"), ResourceCidr: aws.String("198.51.100.0/24"), ResourceType: types.IpamResourceTypePublicIpv4Pool},
+		{ResourceId: aws.String("eipalloc-0123456789abcdef0"), ResourceCidr: aws.String("198.51.100.7/32"), ResourceType: types.IpamResourceTypeEip},
+		{ResourceId: aws.String("vpc-0123456789abcdef1"), ResourceCidr: aws.String("10.0.0.0/16"), ResourceType: types.IpamResourceTypeVpc},
+		{ResourceId: aws.String("invalid"), ResourceCidr: aws.String("not-a-cidr")},
+	}
+
+	var resourceIDs []string
+	for _, resourceCidr := range plugin.GetMostSpecificCidrs(resourceCidrs) {
+		resourceIDs = append(resourceIDs, aws.ToString(resourceCidr.ResourceId))
+	}
+
+	// the second VPC overlaps the first, but doesn't contain the subnet, so it's still reported
+	expectedResourceIDs := []string{"subnet-0123456789abcdef0", "eipalloc-0123456789abcdef0", "vpc-0123456789abcdef1"}
+	if !slices.Equal(resourceIDs, expectedResourceIDs) {
+		t.Errorf("Filtering most specific IPAM resource CIDRs failed; expected %v, received %v", expectedResourceIDs, resourceIDs)
+	}
+}
+
+func TestAddResourceCidrDetails(t *testing.T) {
+	var tests = []struct {
+		resourceType     types.IpamResourceType
+		expectedCloudSvc string
+	}{
+		{types.IpamResourceTypeEip, "eip"},
+		{types.IpamResourceTypeEni, "eni"},
+		{types.IpamResourceTypeSubnet, "vpc"},
+		{types.IpamResourceTypeVpc, "vpc"},
+		{types.IpamResourceTypePublicIpv4Pool, "ipam"},
+	}
+
+	for _, td := range tests {
+		t.Run(string(td.resourceType), func(t *testing.T) {
+			var matchingResource generalResource.Resource
+
+			plugin.AddResourceCidrDetails(&matchingResource, types.IpamResourceCidr{
+				ResourceId:      aws.String("res-0123456789abcdef0"),
+				ResourceName:    aws.String("prod"),
+				ResourceCidr:    aws.String("10.0.1.0/24"),
+				ResourceOwnerId: aws.String("123456789012"),
+				ResourceRegion:  aws.String("us-east-1"),
+				ResourceType:    td.resourceType,
+				VpcId:           aws.String("vpc-0123456789abcdef0"),
+				ResourceTags:    []types.IpamResourceTag{{Key: aws.String("team"), Value: aws.String("network")}},
+			})
+
+			if matchingResource.CloudSvc != td.expectedCloudSvc || matchingResource.AccountID != "123456789012" || matchingResource.Region != "us-east-1" || matchingResource.Name != "prod" {
+				t.Errorf("Adding IPAM resource CIDR details failed; received %+v", matchingResource)
+			}
+
+			expectedAttributes := map[string]string{
+				"Source":       "ipam",
+				"ResourceType": string(td.resourceType),
+				"ResourceCidr": "10.0.1.0/24",
+				"VpcId":        "vpc-0123456789abcdef0",
+			}
+			if !maps.Equal(matchingResource.Attributes, expectedAttributes) {
+				t.Errorf("Adding IPAM resource CIDR details failed; expected attributes %v, received %v", expectedAttributes, matchingResource.Attributes)
+			}
+
+			expectedTags := map[string]string{"team": "network"}
+			if !maps.Equal(matchingResource.Tags, expectedTags) {
+				t.Errorf("Adding IPAM resource CIDR details failed; expected tags %v, received %v", expectedTags, matchingResource.Tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lightsail"
+	"github.com/aws/aws-sdk-go-v2/service/lightsail/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// Lightsail distributions are global, but can only be managed via us-east-1
+const distributionRegion = "us-east-1"
+
+type LightsailPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	NetworkMapping bool
+}
+
+func init() {
+	// Lightsail resources live in their own account-level service, so none of them show up in the EC2 or ELB APIs
+	registry.Register("aws", "lightsail", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return LightsailPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping}
+	})
+}
+
+func tagKeyValue(tag types.Tag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func GetInstanceIPAddrs(instance types.Instance) []string {
+	var instanceIPAddrs []string
+
+	if instance.PublicIpAddress != nil {
+		instanceIPAddrs = append(instanceIPAddrs, *instance.PublicIpAddress)
+	}
+	instanceIPAddrs = append(instanceIPAddrs, instance.Ipv6Addresses...)
+
+	return instanceIPAddrs
+}
+
+func IsRegionUnavailable(err error) bool {
+	// Lightsail is only offered in a subset of regions, and the API endpoint doesn't exist in the others
+	var dnsErr *net.DNSError
+
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+func resolveDNSName(fqdn string) []string {
+	if fqdn == "" {
+		return nil
+	}
+
+	ipAddrs, err := utils.LookupFQDN(fqdn)
+	if err != nil {
+		log.Debug("unable to resolve Lightsail DNS name ", fqdn, ": ", err)
+		return nil
+	}
+
+	return utils.FormatIPAddrs(ipAddrs)
+}
+
+func (lsp LightsailPlugin) GetResources() ([]types.Instance, error) {
+	var instances []types.Instance
+	var pageToken *string
+
+	lsClient := lightsail.NewFromConfig(lsp.AwsConn.AwsConfig)
+
+	for {
+		output, err := lsClient.GetInstances(context.TODO(), &lightsail.GetInstancesInput{PageToken: pageToken})
+		if err != nil {
+			return instances, err
+		}
+
+		instances = append(instances, output.Instances...)
+
+		// the Lightsail API doesn't provide paginators, so page tokens need to be followed manually
+		pageToken = output.NextPageToken
+		if pageToken == nil {
+			break
+		}
+	}
+
+	return instances, nil
+}
+
+func (lsp LightsailPlugin) GetStaticIPs() ([]types.StaticIp, error) {
+	var staticIPs []types.StaticIp
+	var pageToken *string
+
+	lsClient := lightsail.NewFromConfig(lsp.AwsConn.AwsConfig)
+
+	for {
+		output, err := lsClient.GetStaticIps(context.TODO(), &lightsail.GetStaticIpsInput{PageToken: pageToken})
+		if err != nil {
+			return staticIPs, err
+		}
+
+		staticIPs = append(staticIPs, output.StaticIps...)
+
+		pageToken = output.NextPageToken
+		if pageToken == nil {
+			break
+		}
+	}
+
+	return staticIPs, nil
+}
+
+func (lsp LightsailPlugin) GetLoadBalancers() ([]types.LoadBalancer, error) {
+	var loadBalancers []types.LoadBalancer
+	var pageToken *string
+
+	lsClient := lightsail.NewFromConfig(lsp.AwsConn.AwsConfig)
+
+	for {
+		output, err := lsClient.GetLoadBalancers(context.TODO(), &lightsail.GetLoadBalancersInput{PageToken: pageToken})
+		if err != nil {
+			return loadBalancers, err
+		}
+
+		loadBalancers = append(loadBalancers, output.LoadBalancers...)
+
+		pageToken = output.NextPageToken
+		if pageToken == nil {
+			break
+		}
+	}
+
+	return loadBalancers, nil
+}
+
+func (lsp LightsailPlugin) GetDistributions() ([]types.LightsailDistribution, error) {
+	var distros []types.LightsailDistribution
+	var pageToken *string
+
+	lsClient := lightsail.NewFromConfig(lsp.AwsConn.AwsConfig)
+
+	for {
+		output, err := lsClient.GetDistributions(context.TODO(), &lightsail.GetDistributionsInput{PageToken: pageToken})
+		if err != nil {
+			return distros, err
+		}
+
+		distros = append(distros, output.Distributions...)
+
+		pageToken = output.NextPageToken
+		if pageToken == nil {
+			break
+		}
+	}
+
+	return distros, nil
+}
+
+func (lsp LightsailPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	instances, err := lsp.GetResources()
+	if err != nil {
+		if IsRegionUnavailable(err) {
+			log.Debug("Lightsail isn't available in ", lsp.AwsConn.AwsConfig.Region, ", skipping")
+			return matchingResources, nil
+		}
+
+		return matchingResources, err
+	}
+
+	staticIPs, err := lsp.GetStaticIPs()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	loadBalancers, err := lsp.GetLoadBalancers()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	// distributions would otherwise be returned once for every region searched
+	var distros []types.LightsailDistribution
+	if lsp.AwsConn.AwsConfig.Region == distributionRegion {
+		distros, err = lsp.GetDistributions()
+		if err != nil {
+			return matchingResources, err
+		}
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	for _, instance := range instances {
+		instanceIPAddrs := GetInstanceIPAddrs(instance)
+
+		for _, tgt := range ipMatcher.MatchAny(instanceIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(instance.Name)
+			matchingResource.RID = aws.ToString(instance.Arn)
+			matchingResource.Name = matchingResource.Id
+			matchingResource.CloudSvc = "lightsail"
+			matchingResource.Tags = utils.FormatTags(instance.Tags, tagKeyValue)
+			if instance.State != nil {
+				matchingResource.Status = aws.ToString(instance.State.Name)
+			}
+
+			matchingResource.AddAttribute("ResourceType", string(instance.ResourceType))
+			matchingResource.AddAttribute("BlueprintId", aws.ToString(instance.BlueprintId))
+			matchingResource.AddAttribute("BundleId", aws.ToString(instance.BundleId))
+			matchingResource.AddAttribute("IsStaticIp", strconv.FormatBool(aws.ToBool(instance.IsStaticIp)))
+
+			for _, ipAddr := range instanceIPAddrs {
+				matchingResource.AddPublicIPAddr(ipAddr)
+			}
+
+			if lsp.NetworkMapping {
+				if instance.Location != nil {
+					matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(instance.Location.AvailabilityZone))
+				}
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, matchingResource.Id)
+			}
+
+			log.Debug("IP ", tgt, " found as Lightsail instance -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	for _, staticIP := range staticIPs {
+		staticIPAddr := aws.ToString(staticIP.IpAddress)
+
+		for _, tgt := range ipMatcher.Match(staticIPAddr) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(staticIP.Name)
+			matchingResource.RID = aws.ToString(staticIP.Arn)
+			matchingResource.Name = matchingResource.Id
+			matchingResource.CloudSvc = "lightsail"
+			matchingResource.AddPublicIPAddr(staticIPAddr)
+
+			matchingResource.AddAttribute("ResourceType", string(staticIP.ResourceType))
+			matchingResource.AddAttribute("AttachedTo", aws.ToString(staticIP.AttachedTo))
+
+			if aws.ToBool(staticIP.IsAttached) {
+				matchingResource.Status = "attached"
+			} else {
+				// just like unassociated elastic IPs, these are still reserved for the account
+				matchingResource.Status = "unattached"
+				log.Warn("IP ", staticIPAddr, " is a Lightsail static IP (", matchingResource.Id, ") that's allocated to the account, but not attached to any instance")
+			}
+
+			if lsp.NetworkMapping {
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, matchingResource.Id)
+				if staticIP.AttachedTo != nil {
+					matchingResource.NetworkMap = append(matchingResource.NetworkMap, *staticIP.AttachedTo)
+				}
+			}
+
+			log.Debug("IP ", tgt, " found as Lightsail static IP -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	for _, loadBalancer := range loadBalancers {
+		lbIPAddrs := resolveDNSName(aws.ToString(loadBalancer.DnsName))
+
+		for _, tgt := range ipMatcher.MatchAny(lbIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(loadBalancer.Name)
+			matchingResource.RID = aws.ToString(loadBalancer.Arn)
+			matchingResource.Name = matchingResource.Id
+			matchingResource.CloudSvc = "lightsail"
+			matchingResource.Status = string(loadBalancer.State)
+			matchingResource.Tags = utils.FormatTags(loadBalancer.Tags, tagKeyValue)
+
+			matchingResource.AddAttribute("ResourceType", string(loadBalancer.ResourceType))
+			matchingResource.AddAttribute("DnsName", aws.ToString(loadBalancer.DnsName))
+
+			for _, ipAddr := range lbIPAddrs {
+				matchingResource.AddPublicIPAddr(ipAddr)
+			}
+
+			if lsp.NetworkMapping {
+				var tgtInstances []string
+				for _, instanceHealth := range loadBalancer.InstanceHealthSummary {
+					tgtInstances = append(tgtInstances, aws.ToString(instanceHealth.InstanceName))
+				}
+
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(loadBalancer.DnsName), matchingResource.Id, utils.FormatStrSliceAsCSV(tgtInstances))
+			}
+
+			log.Debug("IP ", tgt, " found as Lightsail load balancer -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	for _, distro := range distros {
+		distroIPAddrs := resolveDNSName(aws.ToString(distro.DomainName))
+
+		for _, tgt := range ipMatcher.MatchAny(distroIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(distro.Name)
+			matchingResource.RID = aws.ToString(distro.Arn)
+			matchingResource.Name = matchingResource.Id
+			matchingResource.CloudSvc = "lightsail"
+			matchingResource.Status = aws.ToString(distro.Status)
+			matchingResource.Tags = utils.FormatTags(distro.Tags, tagKeyValue)
+
+			matchingResource.AddAttribute("ResourceType", string(distro.ResourceType))
+			matchingResource.AddAttribute("DomainName", aws.ToString(distro.DomainName))
+			matchingResource.AddAttribute("Enabled", strconv.FormatBool(aws.ToBool(distro.IsEnabled)))
+
+			for _, ipAddr := range distroIPAddrs {
+				matchingResource.AddPublicIPAddr(ipAddr)
+			}
+
+			if lsp.NetworkMapping {
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(distro.DomainName), matchingResource.Id)
+				if distro.Origin != nil {
+					matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(distro.Origin.Name))
+				}
+			}
+
+			log.Debug("IP ", tgt, " found as Lightsail distribution -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (lsp LightsailPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(lsp, tgtIP)
+}
@@ -0,0 +1,100 @@
+package plugin_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lightsail/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/lightsail"
+)
+
+func lspFactory() plugin.LightsailPlugin {
+	ac, _ := awsconnector.New()
+
+	lsp := plugin.LightsailPlugin{AwsConn: ac}
+
+	return lsp
+}
+
+func TestGetResources(t *testing.T) {
+	lsp := lspFactory()
+
+	lsResources, _ := lsp.GetResources()
+
+	expectedType := "Instance"
+	for _, instance := range lsResources {
+		instanceType := reflect.TypeOf(instance)
+		if instanceType.Name() != expectedType {
+			t.Errorf("Fetching resources via Lightsail Plugin failed; wanted %s type, received %s", expectedType, instanceType.Name())
+		}
+	}
+}
+
+func TestGetInstanceIPAddrs(t *testing.T) {
+	var tests = []struct {
+		name            string
+		instance        types.Instance
+		expectedIPAddrs []string
+	}{
+		{"dualStack", types.Instance{PublicIpAddress: aws.String("1.1.1.1"), Ipv6Addresses: []string{"2600:1f18::1"}}, []string{"1.1.1.1", "2600:1f18::1"}},
+		{"ipv6Only", types.Instance{Ipv6Addresses: []string{"2600:1f18::1"}}, []string{"2600:1f18::1"}},
+		{"stopped", types.Instance{}, nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			if instanceIPAddrs := plugin.GetInstanceIPAddrs(td.instance); !slices.Equal(instanceIPAddrs, td.expectedIPAddrs) {
+				t.Errorf("Fetching Lightsail instance IPs failed; expected %v, received %v", td.expectedIPAddrs, instanceIPAddrs)
+			}
+		})
+	}
+}
+
+func TestIsRegionUnavailable(t *testing.T) {
+	var tests = []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"endpointNotFound", fmt.Errorf("operation error Lightsail: GetInstances: %w", &net.DNSError{Name: "lightsail.af-south-1.amazonaws.com", IsNotFound: true}), true},
+		{"dnsTimeout", &net.DNSError{Name: "lightsail.us-east-1.amazonaws.com", IsTimeout: true}, false},
+		{"accessDenied", errors.New("AccessDeniedException"), false},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			if unavailable := plugin.IsRegionUnavailable(td.err); unavailable != td.expected {
+				t.Errorf("Checking Lightsail region availability failed; expected %t, received %t", td.expected, unavailable)
+			}
+		})
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	lsp := lspFactory()
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.ipAddr, func(t *testing.T) {
+			matchedResource, _ := lsp.SearchResources(td.ipAddr)
+			matchedResourceType := reflect.TypeOf(matchedResource)
+
+			if matchedResourceType.Name() != td.expectedType {
+				t.Errorf("Lightsail search failed; expected %s after search, received %s", td.expectedType, matchedResourceType.Name())
+			}
+		})
+	}
+}
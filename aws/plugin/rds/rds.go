@@ -0,0 +1,226 @@
+package plugin
+
+import (
+	"context"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+type RDSPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	NetworkMapping bool
+	PrivateSearch  bool
+	VpcID          string
+}
+
+func init() {
+	// DocumentDB and Neptune are built on top of RDS, so their instances and clusters are returned by the RDS API as well
+	// endpoints of databases that aren't publicly accessible resolve to their private IPs
+	registry.Register("aws", "rds", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return RDSPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping, PrivateSearch: awsCfg.PrivateSearch, VpcID: awsCfg.VpcID}
+	}, registry.PrivateSearch)
+}
+
+func ResolveEndpoint(fqdn string) []string {
+	if fqdn == "" {
+		return nil
+	}
+
+	// endpoints of stopped or private databases may not resolve, which shouldn't prevent the rest of the search
+	endpointIPAddrs, err := utils.LookupFQDN(fqdn)
+	if err != nil {
+		log.Debug("unable to resolve database endpoint ", fqdn, ": ", err)
+		return nil
+	}
+
+	return utils.FormatIPAddrs(endpointIPAddrs)
+}
+
+func tagKeyValue(tag types.Tag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func AddSubnetGroupToNetworkMap(matchingResource *generalResource.Resource, subnetGroup types.DBSubnetGroup) {
+	var subnetIDs []string
+	for _, subnet := range subnetGroup.Subnets {
+		subnetIDs = append(subnetIDs, aws.ToString(subnet.SubnetIdentifier))
+	}
+
+	matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(subnetGroup.VpcId), aws.ToString(subnetGroup.DBSubnetGroupName), utils.FormatStrSliceAsCSV(subnetIDs))
+}
+
+func (rdsp RDSPlugin) GetResources() ([]types.DBInstance, error) {
+	var dbInstances []types.DBInstance
+
+	rdsClient := rds.NewFromConfig(rdsp.AwsConn.AwsConfig)
+	paginator := rds.NewDescribeDBInstancesPaginator(rdsClient, nil)
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return dbInstances, err
+		}
+
+		dbInstances = append(dbInstances, output.DBInstances...)
+	}
+
+	return dbInstances, nil
+}
+
+func (rdsp RDSPlugin) GetClusters() ([]types.DBCluster, error) {
+	var dbClusters []types.DBCluster
+
+	rdsClient := rds.NewFromConfig(rdsp.AwsConn.AwsConfig)
+	paginator := rds.NewDescribeDBClustersPaginator(rdsClient, nil)
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return dbClusters, err
+		}
+
+		dbClusters = append(dbClusters, output.DBClusters...)
+	}
+
+	return dbClusters, nil
+}
+
+func (rdsp RDSPlugin) GetSubnetGroup(subnetGroupName string) (types.DBSubnetGroup, error) {
+	rdsClient := rds.NewFromConfig(rdsp.AwsConn.AwsConfig)
+
+	output, err := rdsClient.DescribeDBSubnetGroups(context.TODO(), &rds.DescribeDBSubnetGroupsInput{
+		DBSubnetGroupName: &subnetGroupName,
+	})
+	if err != nil || len(output.DBSubnetGroups) == 0 {
+		return types.DBSubnetGroup{}, err
+	}
+
+	return output.DBSubnetGroups[0], nil
+}
+
+func (rdsp RDSPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	dbInstances, err := rdsp.GetResources()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	for _, dbInstance := range dbInstances {
+		if dbInstance.Endpoint == nil {
+			// instances that are still being created don't have an endpoint yet
+			continue
+		}
+
+		if rdsp.VpcID != "" && (dbInstance.DBSubnetGroup == nil || aws.ToString(dbInstance.DBSubnetGroup.VpcId) != rdsp.VpcID) {
+			continue
+		}
+
+		dbIPAddrs := ResolveEndpoint(aws.ToString(dbInstance.Endpoint.Address))
+
+		for _, tgt := range ipMatcher.MatchAny(dbIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(dbInstance.DBInstanceIdentifier)
+			matchingResource.RID = aws.ToString(dbInstance.DBInstanceArn)
+			matchingResource.Name = matchingResource.Id
+			matchingResource.CloudSvc = "rds"
+			matchingResource.Status = aws.ToString(dbInstance.DBInstanceStatus)
+			matchingResource.Tags = utils.FormatTags(dbInstance.TagList, tagKeyValue)
+
+			matchingResource.AddAttribute("Engine", aws.ToString(dbInstance.Engine))
+			matchingResource.AddAttribute("PubliclyAccessible", strconv.FormatBool(aws.ToBool(dbInstance.PubliclyAccessible)))
+			matchingResource.AddAttribute("DBClusterIdentifier", aws.ToString(dbInstance.DBClusterIdentifier))
+
+			for _, ipAddr := range dbIPAddrs {
+				matchingResource.AddIPAddr(ipAddr, rdsp.PrivateSearch)
+			}
+
+			if rdsp.NetworkMapping {
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(dbInstance.Endpoint.Address))
+				if dbInstance.DBSubnetGroup != nil {
+					AddSubnetGroupToNetworkMap(&matchingResource, *dbInstance.DBSubnetGroup)
+				}
+			}
+
+			log.Debug("IP ", tgt, " found as RDS instance -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	dbClusters, err := rdsp.GetClusters()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	for _, dbCluster := range dbClusters {
+		// the writer and reader endpoints resolve to the cluster's instances, which may have already been matched above
+		dbClusterEndpoints := []string{aws.ToString(dbCluster.Endpoint), aws.ToString(dbCluster.ReaderEndpoint)}
+		dbClusterEndpoints = append(dbClusterEndpoints, dbCluster.CustomEndpoints...)
+
+		var dbIPAddrs []string
+		for _, endpoint := range dbClusterEndpoints {
+			dbIPAddrs = append(dbIPAddrs, ResolveEndpoint(endpoint)...)
+		}
+
+		for _, tgt := range ipMatcher.MatchAny(dbIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			// clusters only reference their subnet group by name
+			var subnetGroup types.DBSubnetGroup
+			if dbCluster.DBSubnetGroup != nil && (rdsp.VpcID != "" || rdsp.NetworkMapping) {
+				subnetGroup, err = rdsp.GetSubnetGroup(*dbCluster.DBSubnetGroup)
+				if err != nil {
+					return matchingResources, err
+				}
+			}
+			if rdsp.VpcID != "" && aws.ToString(subnetGroup.VpcId) != rdsp.VpcID {
+				continue
+			}
+
+			matchingResource.Id = aws.ToString(dbCluster.DBClusterIdentifier)
+			matchingResource.RID = aws.ToString(dbCluster.DBClusterArn)
+			matchingResource.Name = matchingResource.Id
+			matchingResource.CloudSvc = "rds"
+			matchingResource.Status = aws.ToString(dbCluster.Status)
+			matchingResource.Tags = utils.FormatTags(dbCluster.TagList, tagKeyValue)
+
+			matchingResource.AddAttribute("Engine", aws.ToString(dbCluster.Engine))
+			matchingResource.AddAttribute("PubliclyAccessible", strconv.FormatBool(aws.ToBool(dbCluster.PubliclyAccessible)))
+
+			for _, ipAddr := range dbIPAddrs {
+				matchingResource.AddIPAddr(ipAddr, rdsp.PrivateSearch)
+			}
+
+			if rdsp.NetworkMapping {
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, aws.ToString(dbCluster.Endpoint))
+				if dbCluster.DBSubnetGroup != nil {
+					AddSubnetGroupToNetworkMap(&matchingResource, subnetGroup)
+				}
+			}
+
+			log.Debug("IP ", tgt, " found as RDS cluster -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (rdsp RDSPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(rdsp, tgtIP)
+}
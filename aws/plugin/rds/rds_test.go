@@ -0,0 +1,126 @@
+package plugin_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	redshifttypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/rds"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func rdspFactory() plugin.RDSPlugin {
+	ac, _ := awsconnector.New()
+
+	rdsp := plugin.RDSPlugin{AwsConn: ac}
+
+	return rdsp
+}
+
+func TestGetResources(t *testing.T) {
+	rdsp := rdspFactory()
+
+	rdsResources, _ := rdsp.GetResources()
+
+	expectedType := "DBInstance"
+	for _, dbInstance := range rdsResources {
+		dbInstanceType := reflect.TypeOf(dbInstance)
+		if dbInstanceType.Name() != expectedType {
+			t.Errorf("Fetching resources via RDS Plugin failed; wanted %s type, received %s", expectedType, dbInstanceType.Name())
+		}
+	}
+}
+
+func TestResolveEndpoint_NoEndpoint(t *testing.T) {
+	if endpointIPAddrs := plugin.ResolveEndpoint(""); endpointIPAddrs != nil {
+		t.Errorf("Resolving empty database endpoint failed; expected nil, received %v", endpointIPAddrs)
+	}
+}
+
+func TestAddSubnetGroupToNetworkMap(t *testing.T) {
+	var matchingResource generalResource.Resource
+
+	plugin.AddSubnetGroupToNetworkMap(&matchingResource, rdstypes.DBSubnetGroup{
+		DBSubnetGroupName: aws.String("public-dbs"),
+		VpcId:             aws.String("vpc-a"),
+		Subnets:           []rdstypes.Subnet{{SubnetIdentifier: aws.String("subnet-a")}, {SubnetIdentifier: aws.String("subnet-b")}},
+	})
+
+	expectedNetworkMap := []string{"vpc-a", "public-dbs", "[subnet-a,subnet-b]"}
+	if !slices.Equal(matchingResource.NetworkMap, expectedNetworkMap) {
+		t.Errorf("Adding DB subnet group to network map failed; expected %v, received %v", expectedNetworkMap, matchingResource.NetworkMap)
+	}
+}
+
+func TestGetRedshiftIPAddrs(t *testing.T) {
+	cluster := redshifttypes.Cluster{
+		ClusterNodes: []redshifttypes.ClusterNode{
+			{NodeRole: aws.String("LEADER"), PublicIPAddress: aws.String("1.1.1.1")},
+			{NodeRole: aws.String("COMPUTE-0"), PublicIPAddress: aws.String("1.1.1.2")},
+			{NodeRole: aws.String("COMPUTE-1"), PrivateIPAddress: aws.String("10.0.0.5")},
+		},
+		ElasticIpStatus: &redshifttypes.ElasticIpStatus{ElasticIp: aws.String("8.8.8.8")},
+	}
+
+	expectedIPAddrs := []string{"1.1.1.1", "1.1.1.2", "8.8.8.8"}
+	if clusterIPAddrs := plugin.GetRedshiftIPAddrs(cluster); !slices.Equal(clusterIPAddrs, expectedIPAddrs) {
+		t.Errorf("Fetching Redshift cluster IPs failed; expected %v, received %v", expectedIPAddrs, clusterIPAddrs)
+	}
+}
+
+func TestGetRedshiftClusterARN(t *testing.T) {
+	var tests = []struct {
+		testName    string
+		cluster     redshifttypes.Cluster
+		expectedARN string
+	}{
+		{
+			"namespace",
+			redshifttypes.Cluster{
+				ClusterIdentifier:   aws.String("analytics"),
+				ClusterNamespaceArn: aws.String("arn:aws-us-gov:redshift:us-gov-west-1:123456789012:namespace:a1b2c3d4-5678-90ab-cdef-11111EXAMPLE"),
+			},
+			"arn:aws-us-gov:redshift:us-gov-west-1:123456789012:cluster:analytics",
+		},
+		{"noNamespace", redshifttypes.Cluster{ClusterIdentifier: aws.String("analytics")}, "analytics"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			if clusterARN := plugin.GetRedshiftClusterARN(td.cluster); clusterARN != td.expectedARN {
+				t.Errorf("Building Redshift cluster ARN failed; expected %s, received %s", td.expectedARN, clusterARN)
+			}
+		})
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	rdsp := rdspFactory()
+	ac, _ := awsconnector.New()
+	rsp := plugin.RedshiftPlugin{AwsConn: ac}
+
+	var tests = []struct {
+		ipAddr, expectedType string
+	}{
+		{"1.1.1.1", "Resource"},
+		{"1234.45.9666.1", "Resource"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.ipAddr, func(t *testing.T) {
+			for _, matchedDB := range []func(string) (generalResource.Resource, error){rdsp.SearchResources, rsp.SearchResources} {
+				matchedDBResource, _ := matchedDB(td.ipAddr)
+				matchedDBType := reflect.TypeOf(matchedDBResource)
+
+				if matchedDBType.Name() != td.expectedType {
+					t.Errorf("Database search failed; expected %s after search, received %s", td.expectedType, matchedDBType.Name())
+				}
+			}
+		})
+	}
+}
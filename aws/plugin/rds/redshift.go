@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"context"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/aws/aws-sdk-go-v2/service/redshift/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+type RedshiftPlugin struct {
+	AwsConn        awsconnector.AWSConnector
+	NetworkMapping bool
+}
+
+func init() {
+	registry.Register("aws", "redshift", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		awsCfg := awsconnector.PluginConfigFrom(cfg.PlatformConfig)
+
+		return RedshiftPlugin{AwsConn: awsCfg.AWSConn, NetworkMapping: cfg.NetworkMapping}
+	})
+}
+
+func redshiftTagKeyValue(tag types.Tag) (string, string) {
+	return aws.ToString(tag.Key), aws.ToString(tag.Value)
+}
+
+func GetRedshiftIPAddrs(cluster types.Cluster) []string {
+	var clusterIPAddrs []string
+
+	// publicly accessible clusters list the public IPs of their nodes directly, but the endpoint is resolved too in case an elastic IP is used
+	for _, clusterNode := range cluster.ClusterNodes {
+		if clusterNode.PublicIPAddress != nil {
+			clusterIPAddrs = append(clusterIPAddrs, *clusterNode.PublicIPAddress)
+		}
+	}
+	if cluster.ElasticIpStatus != nil && cluster.ElasticIpStatus.ElasticIp != nil {
+		clusterIPAddrs = append(clusterIPAddrs, *cluster.ElasticIpStatus.ElasticIp)
+	}
+
+	return clusterIPAddrs
+}
+
+// GetRedshiftClusterARN builds the ARN of the cluster, which isn't returned by the Redshift API; the partition, region, and account are taken from the ARN of the cluster's namespace
+func GetRedshiftClusterARN(cluster types.Cluster) string {
+	clusterID := aws.ToString(cluster.ClusterIdentifier)
+
+	namespaceARN, err := arn.Parse(aws.ToString(cluster.ClusterNamespaceArn))
+	if err != nil {
+		return clusterID
+	}
+
+	namespaceARN.Resource = "cluster:" + clusterID
+
+	return namespaceARN.String()
+}
+
+func (rsp RedshiftPlugin) GetResources() ([]types.Cluster, error) {
+	var clusters []types.Cluster
+
+	redshiftClient := redshift.NewFromConfig(rsp.AwsConn.AwsConfig)
+	paginator := redshift.NewDescribeClustersPaginator(redshiftClient, nil)
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return clusters, err
+		}
+
+		clusters = append(clusters, output.Clusters...)
+	}
+
+	return clusters, nil
+}
+
+func (rsp RedshiftPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	clusters, err := rsp.GetResources()
+	if err != nil {
+		return matchingResources, err
+	}
+
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	for _, cluster := range clusters {
+		clusterIPAddrs := GetRedshiftIPAddrs(cluster)
+
+		var endpointAddr string
+		if cluster.Endpoint != nil {
+			endpointAddr = aws.ToString(cluster.Endpoint.Address)
+			clusterIPAddrs = append(clusterIPAddrs, ResolveEndpoint(endpointAddr)...)
+		}
+
+		for _, tgt := range ipMatcher.MatchAny(clusterIPAddrs) {
+			var matchingResource generalResource.Resource
+
+			matchingResource.Id = aws.ToString(cluster.ClusterIdentifier)
+			matchingResource.RID = GetRedshiftClusterARN(cluster)
+			matchingResource.Name = matchingResource.Id
+			matchingResource.CloudSvc = "redshift"
+			matchingResource.Status = aws.ToString(cluster.ClusterStatus)
+
+			matchingResource.AddAttribute("Engine", "redshift")
+			matchingResource.AddAttribute("PubliclyAccessible", strconv.FormatBool(aws.ToBool(cluster.PubliclyAccessible)))
+			matchingResource.AddAttribute("ClusterNamespaceArn", aws.ToString(cluster.ClusterNamespaceArn))
+
+			matchingResource.Tags = utils.FormatTags(cluster.Tags, redshiftTagKeyValue)
+
+			for _, ipAddr := range clusterIPAddrs {
+				matchingResource.AddPublicIPAddr(ipAddr)
+			}
+
+			if rsp.NetworkMapping {
+				matchingResource.NetworkMap = append(matchingResource.NetworkMap, endpointAddr, aws.ToString(cluster.VpcId), aws.ToString(cluster.ClusterSubnetGroupName))
+			}
+
+			log.Debug("IP ", tgt, " found as Redshift cluster -> ", matchingResource.RID, " with network info ", matchingResource.NetworkMap)
+			matchingResources[tgt] = append(matchingResources[tgt], matchingResource)
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (rsp RedshiftPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(rsp, tgtIP)
+}
@@ -0,0 +1,183 @@
+package plugin
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// attributes that hold a DNS name that alias records can target, depending on the service
+var dnsNameAttributes = []string{"DNSName", "DnsName", "DualStackDnsName", "ApiGatewayDomainName", "DomainName"}
+
+type Route53Plugin struct {
+	AwsConn awsconnector.AWSConnector
+}
+
+// RecordIndex maps IPs and DNS names to the Route 53 records that point at them, so any number of resources can be looked up after a single scan of every hosted zone
+type RecordIndex struct {
+	ipAddrRecords  map[string][]generalResource.DNSRecord
+	dnsNameRecords map[string][]generalResource.DNSRecord
+}
+
+func NewRecordIndex() RecordIndex {
+	return RecordIndex{
+		ipAddrRecords:  map[string][]generalResource.DNSRecord{},
+		dnsNameRecords: map[string][]generalResource.DNSRecord{},
+	}
+}
+
+func NormalizeDNSName(dnsName string) string {
+	// Route 53 returns fully-qualified names with a trailing `.` and escapes wildcards as \052, while ELB alias targets are prefixed with `dualstack.`
+	dnsName = strings.ToLower(strings.TrimSuffix(dnsName, "."))
+	dnsName = strings.ReplaceAll(dnsName, `\052`, "*")
+
+	return strings.TrimPrefix(dnsName, "dualstack.")
+}
+
+func NormalizeZoneID(zoneID string) string {
+	// hosted zone IDs are returned as a path, e.g. /hostedzone/Z0123456789
+	return strings.TrimPrefix(zoneID, "/hostedzone/")
+}
+
+// AddRecordSets indexes the zone's A and AAAA records by the IPs they contain, and its alias records by the DNS name they target
+func (recordIdx RecordIndex) AddRecordSets(zone types.HostedZone, recordSets []types.ResourceRecordSet) {
+	for _, recordSet := range recordSets {
+		if recordSet.Type != types.RRTypeA && recordSet.Type != types.RRTypeAaaa {
+			continue
+		}
+
+		dnsRecord := generalResource.DNSRecord{
+			FQDN:   NormalizeDNSName(aws.ToString(recordSet.Name)),
+			Type:   string(recordSet.Type),
+			ZoneID: NormalizeZoneID(aws.ToString(zone.Id)),
+			Alias:  recordSet.AliasTarget != nil,
+		}
+		if zone.Config != nil {
+			dnsRecord.PrivateZone = zone.Config.PrivateZone
+		}
+
+		if dnsRecord.Alias {
+			aliasTgt := NormalizeDNSName(aws.ToString(recordSet.AliasTarget.DNSName))
+			recordIdx.dnsNameRecords[aliasTgt] = append(recordIdx.dnsNameRecords[aliasTgt], dnsRecord)
+
+			continue
+		}
+
+		for _, record := range recordSet.ResourceRecords {
+			ipAddr, err := utils.NormalizeIPAddr(aws.ToString(record.Value))
+			if err != nil {
+				continue
+			}
+
+			recordIdx.ipAddrRecords[ipAddr] = append(recordIdx.ipAddrRecords[ipAddr], dnsRecord)
+		}
+	}
+}
+
+func GetResourceDNSNames(matchingResource generalResource.Resource) []string {
+	var dnsNames []string
+	for _, attr := range dnsNameAttributes {
+		if dnsName, ok := matchingResource.Attributes[attr]; ok {
+			dnsNames = append(dnsNames, NormalizeDNSName(dnsName))
+		}
+	}
+
+	// CloudFront distributions are named after their domain name
+	if matchingResource.CloudSvc == "cloudfront" {
+		dnsNames = append(dnsNames, NormalizeDNSName(matchingResource.Name))
+	}
+
+	return dnsNames
+}
+
+// Lookup returns every record that contains one of the resource's IPs, or that's an alias to one of its DNS names
+func (recordIdx RecordIndex) Lookup(matchingResource generalResource.Resource) []generalResource.DNSRecord {
+	var dnsRecords []generalResource.DNSRecord
+
+	addRecords := func(records []generalResource.DNSRecord) {
+		for _, record := range records {
+			// records with several values, or routing policies (e.g. weighted records), would otherwise be listed more than once
+			if !slices.Contains(dnsRecords, record) {
+				dnsRecords = append(dnsRecords, record)
+			}
+		}
+	}
+
+	// private IPs are only set for private searches, where they're typically referenced by records in private hosted zones
+	for _, ipAddr := range slices.Concat(matchingResource.PublicIPv4Addrs, matchingResource.PublicIPv6Addrs, matchingResource.PrivateIPv4Addrs, matchingResource.PrivateIPv6Addrs) {
+		if normalizedIPAddr, err := utils.NormalizeIPAddr(ipAddr); err == nil {
+			addRecords(recordIdx.ipAddrRecords[normalizedIPAddr])
+		}
+	}
+	for _, dnsName := range GetResourceDNSNames(matchingResource) {
+		addRecords(recordIdx.dnsNameRecords[dnsName])
+	}
+
+	return dnsRecords
+}
+
+func (r53p Route53Plugin) GetResources() ([]types.HostedZone, error) {
+	var hostedZones []types.HostedZone
+
+	r53Client := route53.NewFromConfig(r53p.AwsConn.AwsConfig)
+	paginator := route53.NewListHostedZonesPaginator(r53Client, &route53.ListHostedZonesInput{})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return hostedZones, err
+		}
+
+		hostedZones = append(hostedZones, output.HostedZones...)
+	}
+
+	return hostedZones, nil
+}
+
+func (r53p Route53Plugin) GetRecordSets(zoneID string) ([]types.ResourceRecordSet, error) {
+	var recordSets []types.ResourceRecordSet
+
+	r53Client := route53.NewFromConfig(r53p.AwsConn.AwsConfig)
+	paginator := route53.NewListResourceRecordSetsPaginator(r53Client, &route53.ListResourceRecordSetsInput{
+		HostedZoneId: &zoneID,
+	})
+
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return recordSets, err
+		}
+
+		recordSets = append(recordSets, output.ResourceRecordSets...)
+	}
+
+	return recordSets, nil
+}
+
+func (r53p Route53Plugin) GetRecordIndex() (RecordIndex, error) {
+	recordIdx := NewRecordIndex()
+
+	hostedZones, err := r53p.GetResources()
+	if err != nil {
+		return recordIdx, err
+	}
+
+	for _, hostedZone := range hostedZones {
+		recordSets, err := r53p.GetRecordSets(aws.ToString(hostedZone.Id))
+		if err != nil {
+			return recordIdx, err
+		}
+
+		recordIdx.AddRecordSets(hostedZone, recordSets)
+	}
+
+	return recordIdx, nil
+}
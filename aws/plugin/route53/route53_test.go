@@ -0,0 +1,123 @@
+package plugin_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	plugin "github.com/magneticstain/ip-2-cloudresource/aws/plugin/route53"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+func r53pFactory() plugin.Route53Plugin {
+	ac, _ := awsconnector.New()
+
+	r53p := plugin.Route53Plugin{AwsConn: ac}
+
+	return r53p
+}
+
+func TestGetResources(t *testing.T) {
+	r53p := r53pFactory()
+
+	r53Resources, _ := r53p.GetResources()
+
+	expectedType := "HostedZone"
+	for _, hostedZone := range r53Resources {
+		hostedZoneType := reflect.TypeOf(hostedZone)
+		if hostedZoneType.Name() != expectedType {
+			t.Errorf("Fetching resources via Route 53 Plugin failed; wanted %s type, received %s", expectedType, hostedZoneType.Name())
+		}
+	}
+}
+
+func TestNormalizeDNSName(t *testing.T) {
+	var tests = []struct {
+		dnsName, expectedDNSName string
+	}{
+		{"www.example.com.", "www.example.com"},
+		{"dualstack.My-LB-0123.us-east-1.elb.amazonaws.com.", "my-lb-0123.us-east-1.elb.amazonaws.com"},
+		{`\052.example.com.`, "*.example.com"},
+		{"d111111abcdef8.cloudfront.net", "d111111abcdef8.cloudfront.net"},
+	}
+
+	for _, td := range tests {
+		t.Run(td.dnsName, func(t *testing.T) {
+			if normalizedDNSName := plugin.NormalizeDNSName(td.dnsName); normalizedDNSName != td.expectedDNSName {
+				t.Errorf("Normalizing DNS name failed; expected %s, received %s", td.expectedDNSName, normalizedDNSName)
+			}
+		})
+	}
+}
+
+func TestRecordIndexLookup(t *testing.T) {
+	recordIdx := plugin.NewRecordIndex()
+
+	recordIdx.AddRecordSets(
+		types.HostedZone{Id: aws.String("/hostedzone/Z0123"), Config: &types.HostedZoneConfig{PrivateZone: false}},
+		[]types.ResourceRecordSet{
+			{Name: aws.String("www.example.com."), Type: types.RRTypeA, ResourceRecords: []types.ResourceRecord{{Value: aws.String("1.1.1.1")}, {Value: aws.String("1.1.1.2")}}},
+			{Name: aws.String("www.example.com."), Type: types.RRTypeAaaa, ResourceRecords: []types.ResourceRecord{{Value: aws.String("2600:1f18:0:0::1")}}},
+			{Name: aws.String("app.example.com."), Type: types.RRTypeA, AliasTarget: &types.AliasTarget{DNSName: aws.String("dualstack.web-0123.us-east-1.elb.amazonaws.com.")}},
+			{Name: aws.String("cdn.example.com."), Type: types.RRTypeA, AliasTarget: &types.AliasTarget{DNSName: aws.String("d111111abcdef8.cloudfront.net.")}},
+			{Name: aws.String("mail.example.com."), Type: types.RRTypeMx, ResourceRecords: []types.ResourceRecord{{Value: aws.String("10 1.1.1.1")}}},
+		},
+	)
+	recordIdx.AddRecordSets(
+		types.HostedZone{Id: aws.String("/hostedzone/Z4567"), Config: &types.HostedZoneConfig{PrivateZone: true}},
+		[]types.ResourceRecordSet{
+			{Name: aws.String("web.internal."), Type: types.RRTypeA, AliasTarget: &types.AliasTarget{DNSName: aws.String("web-0123.us-east-1.elb.amazonaws.com.")}},
+			{Name: aws.String("db.internal."), Type: types.RRTypeA, ResourceRecords: []types.ResourceRecord{{Value: aws.String("10.0.0.5")}}},
+		},
+	)
+
+	var tests = []struct {
+		name               string
+		matchingResource   generalResource.Resource
+		expectedDNSRecords []generalResource.DNSRecord
+	}{
+		{
+			"ec2",
+			generalResource.Resource{CloudSvc: "ec2", PublicIPv4Addrs: []string{"1.1.1.1", "1.1.1.2"}, PublicIPv6Addrs: []string{"2600:1f18::1"}},
+			[]generalResource.DNSRecord{
+				{FQDN: "www.example.com", Type: "A", ZoneID: "Z0123"},
+				{FQDN: "www.example.com", Type: "AAAA", ZoneID: "Z0123"},
+			},
+		},
+		{
+			"elbv2",
+			generalResource.Resource{CloudSvc: "elbv2", PublicIPv4Addrs: []string{"3.3.3.3"}, Attributes: map[string]string{"DNSName": "web-0123.us-east-1.elb.amazonaws.com"}},
+			[]generalResource.DNSRecord{
+				{FQDN: "app.example.com", Type: "A", ZoneID: "Z0123", Alias: true},
+				{FQDN: "web.internal", Type: "A", ZoneID: "Z4567", Alias: true, PrivateZone: true},
+			},
+		},
+		{
+			"cloudfront",
+			generalResource.Resource{CloudSvc: "cloudfront", Name: "d111111abcdef8.cloudfront.net"},
+			[]generalResource.DNSRecord{
+				{FQDN: "cdn.example.com", Type: "A", ZoneID: "Z0123", Alias: true},
+			},
+		},
+		{
+			"privateIP",
+			generalResource.Resource{CloudSvc: "ec2", PrivateIPv4Addrs: []string{"10.0.0.5"}},
+			[]generalResource.DNSRecord{
+				{FQDN: "db.internal", Type: "A", ZoneID: "Z4567", PrivateZone: true},
+			},
+		},
+		{"noRecords", generalResource.Resource{CloudSvc: "ec2", PublicIPv4Addrs: []string{"8.8.8.8"}}, nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			if dnsRecords := recordIdx.Lookup(td.matchingResource); !slices.Equal(dnsRecords, td.expectedDNSRecords) {
+				t.Errorf("Looking up Route 53 records failed; expected %v, received %v", td.expectedDNSRecords, dnsRecords)
+			}
+		})
+	}
+}
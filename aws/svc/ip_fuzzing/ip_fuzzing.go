@@ -1,7 +1,10 @@
 package ipfuzzing
 
 import (
+	"net/netip"
 	"regexp"
+	"slices"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
@@ -10,6 +13,39 @@ import (
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
+type FuzzResult struct {
+	Service, Region, NetworkBorderGroup string
+	// every prefix containing the IP, ranked from most to least specific; useful for debugging
+	Candidates []awsipprefix.GenericAWSPrefix
+}
+
+// services whose IP space is shared by every AWS customer, meaning their IPs can never be attributed to a single customer resource
+var awsManagedSvcs = []string{
+	"AMAZON_APPFLOW",
+	"AMAZON_CONNECT",
+	"CHIME_MEETINGS",
+	"CHIME_VOICECONNECTOR",
+	"CLOUD9",
+	"CLOUDFRONT_ORIGIN_FACING",
+	"CODEBUILD",
+	"DYNAMODB",
+	"EBS",
+	"EC2_INSTANCE_CONNECT",
+	"IVS_REALTIME",
+	"KINESIS_VIDEO_STREAMS",
+	"MEDIA_PACKAGE_V2",
+	"ROUTE53",
+	"ROUTE53_HEALTHCHECKS",
+	"ROUTE53_HEALTHCHECKS_PUBLISHING",
+	"ROUTE53_RESOLVER",
+	"S3",
+	"WORKSPACES_GATEWAYS",
+}
+
+func IsAWSManagedSvc(svc string) bool {
+	return slices.Contains(awsManagedSvcs, strings.ToUpper(svc))
+}
+
 func MapFQDNToSvc(fqdn string) (string, error) {
 	var re *regexp.Regexp
 	var svcName string
@@ -59,71 +95,77 @@ func RunAdvancedFuzzing(ipAddr string) (string, error) {
 	return cloudSvc, nil
 }
 
-func FuzzIP(ipAddr string, attemptAdvancedFuzzing bool) (string, error) {
-	var cloudSvc string
+func FuzzIP(ipAddr string, attemptAdvancedFuzzing bool, ipRangeSrc IPRangeSource) (FuzzResult, error) {
+	var fuzzResult FuzzResult
 
-	awsIPSet, err := FetchIPRanges()
+	prefixIndex, err := GetPrefixIndex(ipRangeSrc)
 	if err != nil {
-		return cloudSvc, err
+		return fuzzResult, err
 	}
 	log.Debug("AWS public IP dataset loaded")
 
-	// AWS divides their prefixes by IP version, so we should determine that first to reduce the number of checks needed
-	// Here, we're checking the IP version and then converting the prefixes for the given version to generic prefixes
-	var ipPrefixSet []awsipprefix.GenericAWSPrefix
 	parsedIPVer, err := utils.DetermineIpAddrVersion(ipAddr)
 	if err != nil {
-		return cloudSvc, err
+		return fuzzResult, err
 	}
 
-	if parsedIPVer == 4 {
-		// IPv4
-		ipPrefixSet, err = ConvertIPPrefixesToGeneric(awsIPSet.Prefixes, nil)
-	} else {
-		// IPv6
-		ipPrefixSet, err = ConvertIPPrefixesToGeneric(nil, awsIPSet.IPv6Prefixes)
-	}
+	parsedIPAddr, err := netip.ParseAddr(ipAddr)
 	if err != nil {
-		log.Error("not able to convert versioned IP prefix groups to generic; [ ERR: ", err, " ]")
-	} else {
-		log.Debug("IP prefix set reduced by version successfully")
+		return fuzzResult, err
 	}
 
-	fuzzedSvc, err := ResolveIPAddrToCloudSvc(ipAddr, ipPrefixSet)
-	if err != nil {
-		return cloudSvc, err
+	// AWS publishes overlapping prefixes (e.g. AMAZON and EC2), so we collect every prefix containing the IP, ranked from most to least specific
+	fuzzResult.Candidates = prefixIndex.Lookup(parsedIPAddr)
+
+	for _, candidate := range fuzzResult.Candidates {
+		log.Debug("IP prefix candidate found: ", candidate.IPRange, " (service: ", candidate.Service, ", region: ", candidate.Region, ")")
 	}
+
+	matchedPrefix := SelectMostSpecificPrefix(fuzzResult.Candidates)
+	fuzzedSvc := matchedPrefix.Service
+
+	// the region is useful to narrow down the search, even if the service itself couldn't be determined
+	fuzzResult.Region = matchedPrefix.Region
+	fuzzResult.NetworkBorderGroup = matchedPrefix.NetworkBorderGroup
+	if fuzzResult.Region != "" {
+		log.Debug("basic IP fuzzing determined the IP belongs to the ", fuzzResult.Region, " region (network border group: ", fuzzResult.NetworkBorderGroup, ")")
+	}
+
 	// if AWS IP range scanning doesn't work, we can try advanced fuzzing, which uses reverse DNS and heuristics to try to determine the service
 	// NOTE: this only works for IPv4 at this time as AWS doesn't appear to have PTR records setup for their IPv6 prefixes
 	if parsedIPVer == 6 {
 		log.Debug("skipping advanced fuzzing since IPv6 is not supported by this feature")
-	} else if fuzzedSvc == "" || fuzzedSvc == "AMAZON" {
+	} else if fuzzedSvc == "" || fuzzedSvc == genericAWSSvcName {
 		log.Debug("basic IP fuzzing failed to determine cloud service")
 
 		if attemptAdvancedFuzzing {
 			log.Debug("starting advanced IP fuzzing")
 			advFuzzResult, err := RunAdvancedFuzzing(ipAddr)
 			if err != nil {
-				return cloudSvc, err
+				return fuzzResult, err
 			}
 
 			if advFuzzResult != "" {
-				return advFuzzResult, nil
+				fuzzResult.Service = advFuzzResult
+
+				return fuzzResult, nil
 			}
 		}
 	} else {
 		// cloud service was found
 		log.Debug("basic IP fuzzing determined the IP belongs to the ", fuzzedSvc, " service")
-		return fuzzedSvc, nil
+		fuzzResult.Service = fuzzedSvc
+
+		return fuzzResult, nil
 	}
 
-	if fuzzedSvc == "AMAZON" || fuzzedSvc == "" {
+	if fuzzedSvc == genericAWSSvcName || fuzzedSvc == "" {
 		// AWS's generic service name for ranges
 		normalizedSvcName := "UNKNOWN"
-		cloudSvc = normalizedSvcName
+		fuzzResult.Service = normalizedSvcName
 	} else {
-		cloudSvc = fuzzedSvc
+		fuzzResult.Service = fuzzedSvc
 	}
 
-	return cloudSvc, nil
+	return fuzzResult, nil
 }
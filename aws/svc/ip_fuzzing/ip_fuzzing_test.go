@@ -2,11 +2,18 @@ package ipfuzzing_test
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"golang.org/x/exp/slices" // Update to the stable `slices` package once 1.12 becomes oldstable ( Issue #112 )
 
 	ipfuzzing "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing"
+	awsipprefix "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing/models/aws_ip_prefix"
 )
 
 func GetValidCloudSvcs(includeUnknownSvc bool) *[]string {
@@ -137,14 +144,299 @@ func TestFuzzIP(t *testing.T) {
 		validSvcs := GetValidCloudSvcs(true)
 
 		t.Run(testName, func(t *testing.T) {
-			svcName, err := ipfuzzing.FuzzIP(td.ipAddr, td.useAdvFuzzing)
+			fuzzResult, err := ipfuzzing.FuzzIP(td.ipAddr, td.useAdvFuzzing, ipfuzzing.DefaultIPRangeSource())
 			if err != nil {
 				t.Errorf("unexpected error received when attempting to fuzz %s IP using general fuzzing: %s", td.ipAddr, err)
 			}
 
-			if !slices.Contains[[]string, string](*validSvcs, svcName) {
-				t.Errorf("unexpected service name when performing IP fuzzing tests; received %s", svcName)
+			if !slices.Contains[[]string, string](*validSvcs, fuzzResult.Service) {
+				t.Errorf("unexpected service name when performing IP fuzzing tests; received %s", fuzzResult.Service)
 			}
 		})
 	}
 }
+
+func overlappingPrefixIndexFactory(t *testing.T) *ipfuzzing.PrefixIndex {
+	// order is intentionally shuffled so that the least specific prefixes are inserted first
+	ipPrefixSet := []awsipprefix.GenericAWSPrefix{
+		{IPRange: "52.0.0.0/11", Region: "us-east-1", Service: "AMAZON", NetworkBorderGroup: "us-east-1"},
+		{IPRange: "52.4.0.0/14", Region: "us-east-1", Service: "EC2", NetworkBorderGroup: "us-east-1"},
+		{IPRange: "52.4.175.0/24", Region: "us-east-1", Service: "AMAZON", NetworkBorderGroup: "us-east-1"},
+		{IPRange: "52.4.175.0/26", Region: "us-east-1", Service: "ROUTE53_HEALTHCHECKS", NetworkBorderGroup: "us-east-1"},
+		{IPRange: "54.0.0.0/8", Region: "us-west-2", Service: "AMAZON", NetworkBorderGroup: "us-west-2"},
+	}
+
+	prefixIndex := ipfuzzing.NewPrefixIndex()
+	for _, ipPrefix := range ipPrefixSet {
+		err := prefixIndex.Insert(ipPrefix)
+		if err != nil {
+			t.Fatalf("unexpected error received when building prefix index: %s", err)
+		}
+	}
+
+	return prefixIndex
+}
+
+func TestPrefixIndexLookup_OverlappingPrefixes(t *testing.T) {
+	prefixIndex := overlappingPrefixIndexFactory(t)
+
+	var tests = []struct {
+		ipAddr           string
+		expectedIPRanges []string
+	}{
+		{"52.4.175.1", []string{"52.4.175.0/26", "52.4.175.0/24", "52.4.0.0/14", "52.0.0.0/11"}},
+		{"52.4.175.237", []string{"52.4.175.0/24", "52.4.0.0/14", "52.0.0.0/11"}},
+		{"52.6.1.1", []string{"52.4.0.0/14", "52.0.0.0/11"}},
+		{"54.1.1.1", []string{"54.0.0.0/8"}},
+		{"1.1.1.1", nil},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			var matchedIPRanges []string
+			for _, prefix := range prefixIndex.Lookup(netip.MustParseAddr(td.ipAddr)) {
+				matchedIPRanges = append(matchedIPRanges, prefix.IPRange)
+			}
+
+			if !slices.Equal(matchedIPRanges, td.expectedIPRanges) {
+				t.Errorf("failed to rank matching prefixes; EXPECTED: %v , RECEIVED: %v , IP: %s", td.expectedIPRanges, matchedIPRanges, td.ipAddr)
+			}
+		})
+	}
+}
+
+func TestSelectMostSpecificPrefix(t *testing.T) {
+	prefixIndex := overlappingPrefixIndexFactory(t)
+
+	var tests = []struct {
+		ipAddr, cloudSvc string
+	}{
+		{"52.4.175.1", "ROUTE53_HEALTHCHECKS"}, // most specific prefix wins
+		{"52.4.175.237", "EC2"},                // generic /24 loses to less specific, non-generic /14
+		{"52.6.1.1", "EC2"},
+		{"54.1.1.1", "AMAZON"}, // only generic prefixes available
+		{"1.1.1.1", ""},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			matchedPrefix := ipfuzzing.SelectMostSpecificPrefix(prefixIndex.Lookup(netip.MustParseAddr(td.ipAddr)))
+
+			if matchedPrefix.Service != td.cloudSvc {
+				t.Errorf("failed to resolve IP to most specific cloud service; EXPECTED SVC: %s , RESOLVED SVC: %s , IP: %s", td.cloudSvc, matchedPrefix.Service, td.ipAddr)
+			}
+		})
+	}
+}
+
+func TestNewPrefixIndexFromIPRanges(t *testing.T) {
+	ipRangeData := awsipprefix.RawAwsIPRangeJSON{
+		Prefixes: []awsipprefix.AwsIpv4Prefix{
+			{IPPrefix: "35.170.0.0/15", Region: "us-east-1", Service: "EC2", NetworkBorderGroup: "us-east-1"},
+			{IPPrefix: "35.168.0.0/13", Region: "us-east-1", Service: "AMAZON", NetworkBorderGroup: "us-east-1"},
+		},
+		IPv6Prefixes: []awsipprefix.AwsIpv6Prefix{
+			{IPv6Prefix: "2600:1f18::/33", Region: "us-east-1", Service: "EC2", NetworkBorderGroup: "us-east-1"},
+			{IPv6Prefix: "2600:1f00::/24", Region: "GLOBAL", Service: "AMAZON", NetworkBorderGroup: "GLOBAL"},
+		},
+	}
+
+	prefixIndex, err := ipfuzzing.NewPrefixIndexFromIPRanges(ipRangeData)
+	if err != nil {
+		t.Fatalf("unexpected error received when building prefix index: %s", err)
+	}
+
+	if prefixIndex.PrefixCnt != 4 {
+		t.Errorf("unexpected prefix count in prefix index; expected 4, received %d", prefixIndex.PrefixCnt)
+	}
+
+	var tests = []struct {
+		ipAddr           string
+		expectedIPRanges []string
+	}{
+		{"35.170.192.9", []string{"35.170.0.0/15", "35.168.0.0/13"}},
+		{"::ffff:35.170.192.9", []string{"35.170.0.0/15", "35.168.0.0/13"}}, // IPv4-mapped IPv6 address
+		{"35.168.1.1", []string{"35.168.0.0/13"}},
+		{"2600:1f18:243e:1300:4685:5a7:7c28:c53a", []string{"2600:1f18::/33", "2600:1f00::/24"}},
+		{"2600:1f99::1", []string{"2600:1f00::/24"}},
+		{"1.1.1.1", nil},
+		{"2001:db8::1", nil},
+	}
+
+	for _, td := range tests {
+		testName := td.ipAddr
+
+		t.Run(testName, func(t *testing.T) {
+			var matchedIPRanges []string
+			for _, prefix := range prefixIndex.Lookup(netip.MustParseAddr(td.ipAddr)) {
+				matchedIPRanges = append(matchedIPRanges, prefix.IPRange)
+			}
+
+			if !slices.Equal(matchedIPRanges, td.expectedIPRanges) {
+				t.Errorf("prefix index lookup failed; EXPECTED: %v , RECEIVED: %v , IP: %s", td.expectedIPRanges, matchedIPRanges, td.ipAddr)
+			}
+		})
+	}
+}
+
+func TestPrefixIndexInsert_InvalidPrefixes(t *testing.T) {
+	var tests = []struct {
+		ipRange string
+	}{
+		{"35.170.0.0"},
+		{"35.170.0.0/33"},
+		{"2600:1f18::/129"},
+		{"not_a_prefix"},
+	}
+
+	for _, td := range tests {
+		testName := td.ipRange
+
+		t.Run(testName, func(t *testing.T) {
+			prefixIndex := ipfuzzing.NewPrefixIndex()
+
+			err := prefixIndex.Insert(awsipprefix.GenericAWSPrefix{IPRange: td.ipRange})
+			if err == nil {
+				t.Errorf("expected error when inserting invalid prefix into prefix index, but didn't")
+			}
+		})
+	}
+}
+
+const testIPRangeJSON string = `{"syncToken": "1700000000", "createDate": "2023-11-14-22-13-20", "prefixes": [{"ip_prefix": "35.170.0.0/15", "region": "us-east-1", "service": "EC2", "network_border_group": "us-east-1"}], "ipv6_prefixes": []}`
+
+func ipRangeServerFactory(reqCnt *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*reqCnt++
+
+		etag := `"test-etag"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(testIPRangeJSON))
+	}))
+}
+
+func TestFetchIPRanges_Cache(t *testing.T) {
+	var reqCnt int
+	srv := ipRangeServerFactory(&reqCnt)
+	defer srv.Close()
+
+	ipRangeSrc := ipfuzzing.IPRangeSource{
+		CacheDir: t.TempDir(),
+		CacheTTL: time.Hour,
+		URL:      srv.URL,
+	}
+
+	var tests = []struct {
+		testName       string
+		cacheTTL       time.Duration
+		expectedReqCnt int
+	}{
+		{"initialFetch", time.Hour, 1},
+		{"cachedCopyWithinTTL", time.Hour, 1},
+		{"conditionalRefreshAfterTTL", 0, 2},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			ipRangeSrc.CacheTTL = td.cacheTTL
+
+			ipRangeData, err := ipfuzzing.FetchIPRanges(ipRangeSrc)
+			if err != nil {
+				t.Errorf("unexpected error received when fetching IP ranges: %s", err)
+			}
+
+			if ipRangeData.SyncToken != "1700000000" || len(ipRangeData.Prefixes) != 1 {
+				t.Errorf("unexpected IP range data returned; received sync token %s with %d prefixes", ipRangeData.SyncToken, len(ipRangeData.Prefixes))
+			}
+
+			if reqCnt != td.expectedReqCnt {
+				t.Errorf("unexpected number of requests made to fetch IP ranges; expected %d, received %d", td.expectedReqCnt, reqCnt)
+			}
+		})
+	}
+
+	// the stale cached copy should be used when AWS can't be reached
+	srv.Close()
+	ipRangeSrc.CacheTTL = 0
+
+	ipRangeData, err := ipfuzzing.FetchIPRanges(ipRangeSrc)
+	if err != nil {
+		t.Errorf("expected stale cached copy of IP ranges to be used when fetch fails, but received error: %s", err)
+	}
+	if ipRangeData.SyncToken != "1700000000" {
+		t.Errorf("unexpected IP range data returned from stale cache; received sync token %s", ipRangeData.SyncToken)
+	}
+}
+
+func TestFetchIPRanges_Offline(t *testing.T) {
+	var reqCnt int
+	srv := ipRangeServerFactory(&reqCnt)
+	defer srv.Close()
+
+	cacheDir := t.TempDir()
+
+	_, err := ipfuzzing.FetchIPRanges(ipfuzzing.IPRangeSource{CacheDir: cacheDir, Offline: true, URL: srv.URL})
+	if err == nil {
+		t.Errorf("expected error when fetching IP ranges in offline mode without a cached copy, but didn't")
+	}
+
+	// populate the cache, then try again
+	_, err = ipfuzzing.FetchIPRanges(ipfuzzing.IPRangeSource{CacheDir: cacheDir, URL: srv.URL})
+	if err != nil {
+		t.Fatalf("unexpected error received when fetching IP ranges: %s", err)
+	}
+
+	ipRangeData, err := ipfuzzing.FetchIPRanges(ipfuzzing.IPRangeSource{CacheDir: cacheDir, Offline: true, URL: srv.URL})
+	if err != nil {
+		t.Errorf("unexpected error received when fetching IP ranges in offline mode: %s", err)
+	}
+	if ipRangeData.SyncToken != "1700000000" {
+		t.Errorf("unexpected IP range data returned in offline mode; received sync token %s", ipRangeData.SyncToken)
+	}
+	if reqCnt != 1 {
+		t.Errorf("unexpected number of requests made to fetch IP ranges; expected 1, received %d", reqCnt)
+	}
+}
+
+func TestFetchIPRanges_LocalFile(t *testing.T) {
+	var tests = []struct {
+		testName, fileContents string
+		expectErr              bool
+	}{
+		{"validFile", testIPRangeJSON, false},
+		{"invalidJSON", "{not json", true},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "ip-ranges.json")
+			err := os.WriteFile(filePath, []byte(td.fileContents), 0o644)
+			if err != nil {
+				t.Fatalf("failed to write test IP range file: %s", err)
+			}
+
+			ipRangeData, err := ipfuzzing.FetchIPRanges(ipfuzzing.IPRangeSource{FilePath: filePath, Offline: true})
+			if td.expectErr {
+				if err == nil {
+					t.Errorf("expected error when loading invalid IP range file, but didn't")
+				}
+			} else if err != nil || len(ipRangeData.Prefixes) != 1 {
+				t.Errorf("failed to load IP ranges from local file; received %d prefixes with error: %v", len(ipRangeData.Prefixes), err)
+			}
+		})
+	}
+
+	_, err := ipfuzzing.FetchIPRanges(ipfuzzing.IPRangeSource{FilePath: filepath.Join(t.TempDir(), "missing.json")})
+	if err == nil {
+		t.Errorf("expected error when loading missing IP range file, but didn't")
+	}
+}
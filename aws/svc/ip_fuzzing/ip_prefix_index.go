@@ -0,0 +1,152 @@
+package ipfuzzing
+
+import (
+	"net/netip"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	awsipprefix "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing/models/aws_ip_prefix"
+)
+
+// IPv4 prefixes are stored as IPv4-mapped IPv6 prefixes ( ::ffff:0:0/96 ) so both IP versions can share a single trie
+const ipv4MappedPrefixLen int = 96
+
+type prefixTrieNode struct {
+	children [2]*prefixTrieNode
+	// prefixes ending at this node, kept in insertion order
+	prefixes []awsipprefix.GenericAWSPrefix
+}
+
+type PrefixIndex struct {
+	root      *prefixTrieNode
+	PrefixCnt int
+}
+
+var (
+	sharedPrefixIndex     *PrefixIndex
+	sharedPrefixIndexErr  error
+	sharedPrefixIndexOnce sync.Once
+)
+
+func NewPrefixIndex() *PrefixIndex {
+	return &PrefixIndex{root: &prefixTrieNode{}}
+}
+
+func NewPrefixIndexFromIPRanges(ipRangeData awsipprefix.RawAwsIPRangeJSON) (*PrefixIndex, error) {
+	prefixIndex := NewPrefixIndex()
+
+	for _, prefix := range ipRangeData.Prefixes {
+		err := prefixIndex.Insert(awsipprefix.GenericAWSPrefix{
+			IPRange:            prefix.IPPrefix,
+			Region:             prefix.Region,
+			Service:            prefix.Service,
+			NetworkBorderGroup: prefix.NetworkBorderGroup,
+		})
+		if err != nil {
+			return prefixIndex, err
+		}
+	}
+
+	for _, prefix := range ipRangeData.IPv6Prefixes {
+		err := prefixIndex.Insert(awsipprefix.GenericAWSPrefix{
+			IPRange:            prefix.IPv6Prefix,
+			Region:             prefix.Region,
+			Service:            prefix.Service,
+			NetworkBorderGroup: prefix.NetworkBorderGroup,
+		})
+		if err != nil {
+			return prefixIndex, err
+		}
+	}
+
+	return prefixIndex, nil
+}
+
+func GetPrefixIndex(ipRangeSrc IPRangeSource) (*PrefixIndex, error) {
+	// the index is only built once per process since the AWS IP ranges won't change mid-run
+	sharedPrefixIndexOnce.Do(func() {
+		var ipRangeData awsipprefix.RawAwsIPRangeJSON
+
+		ipRangeData, sharedPrefixIndexErr = FetchIPRanges(ipRangeSrc)
+		if sharedPrefixIndexErr != nil {
+			return
+		}
+
+		sharedPrefixIndex, sharedPrefixIndexErr = NewPrefixIndexFromIPRanges(ipRangeData)
+		if sharedPrefixIndexErr == nil {
+			log.Debug("AWS IP prefix index built with [ ", sharedPrefixIndex.PrefixCnt, " ] prefixes")
+		}
+	})
+
+	return sharedPrefixIndex, sharedPrefixIndexErr
+}
+
+func normalizeAddr(addr netip.Addr) ([16]byte, int) {
+	// returns the 16-byte form of the address, along with the number of leading bits used to map IPv4 addresses into IPv6 space
+	if addr.Is4() {
+		return addr.As16(), ipv4MappedPrefixLen
+	}
+
+	return addr.As16(), 0
+}
+
+func getBit(addrBytes [16]byte, i int) byte {
+	return (addrBytes[i/8] >> (7 - i%8)) & 1
+}
+
+func (idx *PrefixIndex) Insert(ipPrefix awsipprefix.GenericAWSPrefix) error {
+	parsedPrefix, err := netip.ParsePrefix(ipPrefix.IPRange)
+	if err != nil {
+		return err
+	}
+	parsedPrefix = parsedPrefix.Masked()
+
+	addrBytes, bitOffset := normalizeAddr(parsedPrefix.Addr())
+	prefixLen := parsedPrefix.Bits() + bitOffset
+
+	node := idx.root
+	for i := 0; i < prefixLen; i++ {
+		bit := getBit(addrBytes, i)
+
+		if node.children[bit] == nil {
+			node.children[bit] = &prefixTrieNode{}
+		}
+		node = node.children[bit]
+	}
+
+	node.prefixes = append(node.prefixes, ipPrefix)
+	idx.PrefixCnt++
+
+	return nil
+}
+
+func (idx *PrefixIndex) Lookup(addr netip.Addr) []awsipprefix.GenericAWSPrefix {
+	// walk the trie along the address's bits, collecting every prefix found on the way down; this is O(prefix length)
+	var matchedPrefixes []awsipprefix.GenericAWSPrefix
+	var matchedLevels [][]awsipprefix.GenericAWSPrefix
+
+	addr = addr.Unmap().WithZone("")
+	addrBytes, bitOffset := normalizeAddr(addr)
+	maxDepth := addr.BitLen() + bitOffset
+
+	node := idx.root
+	for i := 0; node != nil; i++ {
+		if len(node.prefixes) > 0 {
+			matchedLevels = append(matchedLevels, node.prefixes)
+		}
+
+		if i == maxDepth {
+			break
+		}
+
+		node = node.children[getBit(addrBytes, i)]
+	}
+
+	// deepest levels are the most specific, while prefixes of equal length stay in insertion order
+	for i := len(matchedLevels) - 1; i >= 0; i-- {
+		matchedPrefixes = append(matchedPrefixes, matchedLevels[i]...)
+	}
+
+	return matchedPrefixes
+}
@@ -0,0 +1,239 @@
+package ipfuzzing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	awsipprefix "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing/models/aws_ip_prefix"
+)
+
+const DefaultIPRangeCacheTTL time.Duration = 24 * time.Hour
+
+const ipRangeCacheDirName string = "ip-2-cloudresource"
+const ipRangeCacheFileName string = "ip-ranges.json"
+const ipRangeCacheMetadataFileName string = "ip-ranges.meta.json"
+
+type IPRangeSource struct {
+	// directory to cache ip-ranges.json in; defaults to a subdirectory of the user's cache directory
+	CacheDir string
+	// how long a cached copy is used before being revalidated against AWS; zero always revalidates
+	CacheTTL time.Duration
+	// user-supplied copy of ip-ranges.json; takes precedence over both the cache and AWS
+	FilePath string
+	// never reach out to AWS; only the user-supplied file or cached copy is used
+	Offline bool
+	// alternate location to fetch ip-ranges.json from (e.g. an internal mirror); defaults to AWS
+	URL string
+}
+
+type ipRangeCacheMetadata struct {
+	SyncToken    string    `json:"syncToken"`
+	CreateDate   string    `json:"createDate"`
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"lastModified"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+}
+
+func DefaultIPRangeSource() IPRangeSource {
+	return IPRangeSource{CacheTTL: DefaultIPRangeCacheTTL}
+}
+
+func (ipRangeSrc IPRangeSource) getCacheDir() (string, error) {
+	if ipRangeSrc.CacheDir != "" {
+		return ipRangeSrc.CacheDir, nil
+	}
+
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(userCacheDir, ipRangeCacheDirName), nil
+}
+
+func (ipRangeSrc IPRangeSource) getURL() string {
+	if ipRangeSrc.URL != "" {
+		return ipRangeSrc.URL
+	}
+
+	return awsIPRangeURL
+}
+
+func ReadIPRangeFile(filePath string) (awsipprefix.RawAwsIPRangeJSON, error) {
+	var ipRangeData awsipprefix.RawAwsIPRangeJSON
+
+	jsonData, err := os.ReadFile(filePath)
+	if err != nil {
+		return ipRangeData, err
+	}
+
+	return parseIPRanges(jsonData)
+}
+
+func readIPRangeCache(cacheDir string) (awsipprefix.RawAwsIPRangeJSON, ipRangeCacheMetadata, error) {
+	var ipRangeData awsipprefix.RawAwsIPRangeJSON
+	var cacheMetadata ipRangeCacheMetadata
+
+	rawMetadata, err := os.ReadFile(filepath.Join(cacheDir, ipRangeCacheMetadataFileName))
+	if err != nil {
+		return ipRangeData, cacheMetadata, err
+	}
+
+	err = json.Unmarshal(rawMetadata, &cacheMetadata)
+	if err != nil {
+		return ipRangeData, cacheMetadata, err
+	}
+
+	ipRangeData, err = ReadIPRangeFile(filepath.Join(cacheDir, ipRangeCacheFileName))
+	if err != nil {
+		return ipRangeData, cacheMetadata, err
+	}
+
+	// the cache is keyed by the sync token and creation date, so a mismatch means the cache was only partially written or was tampered with
+	if ipRangeData.SyncToken != cacheMetadata.SyncToken || ipRangeData.CreateDate != cacheMetadata.CreateDate {
+		return ipRangeData, cacheMetadata, errors.New("cached AWS IP ranges do not match cache metadata")
+	}
+
+	return ipRangeData, cacheMetadata, nil
+}
+
+func writeCacheFile(filePath string, data []byte) error {
+	// write to a temp file first and move it into place so that concurrent runs never see a partially written file
+	tmpFile, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name()) //nolint:errcheck
+
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), filePath)
+}
+
+func writeIPRangeCache(cacheDir string, jsonData []byte, cacheMetadata ipRangeCacheMetadata) error {
+	err := os.MkdirAll(cacheDir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	rawMetadata, err := json.Marshal(cacheMetadata)
+	if err != nil {
+		return err
+	}
+
+	if jsonData != nil {
+		err = writeCacheFile(filepath.Join(cacheDir, ipRangeCacheFileName), jsonData)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeCacheFile(filepath.Join(cacheDir, ipRangeCacheMetadataFileName), rawMetadata)
+}
+
+func FetchIPRanges(ipRangeSrc IPRangeSource) (awsipprefix.RawAwsIPRangeJSON, error) {
+	var ipRangeData awsipprefix.RawAwsIPRangeJSON
+
+	if ipRangeSrc.FilePath != "" {
+		log.Debug("loading AWS IP ranges from local file: ", ipRangeSrc.FilePath)
+
+		return ReadIPRangeFile(ipRangeSrc.FilePath)
+	}
+
+	cacheDir, err := ipRangeSrc.getCacheDir()
+	if err != nil {
+		if ipRangeSrc.Offline {
+			return ipRangeData, fmt.Errorf("offline mode enabled, but the IP range cache directory could not be determined: %w", err)
+		}
+
+		log.Warn("unable to determine IP range cache directory; caching will be disabled: ", err)
+	}
+
+	var cachedIPRangeData awsipprefix.RawAwsIPRangeJSON
+	var cacheMetadata ipRangeCacheMetadata
+	cacheErr := err
+	if cacheDir != "" {
+		cachedIPRangeData, cacheMetadata, cacheErr = readIPRangeCache(cacheDir)
+		if cacheErr != nil {
+			log.Debug("no usable cached copy of AWS IP ranges found: ", cacheErr)
+		}
+	}
+
+	if ipRangeSrc.Offline {
+		if cacheErr != nil {
+			return ipRangeData, fmt.Errorf("offline mode enabled, but no cached copy of AWS IP ranges is available: %w", cacheErr)
+		}
+
+		log.Debug("offline mode enabled; using cached AWS IP ranges (sync token: ", cacheMetadata.SyncToken, ")")
+
+		return cachedIPRangeData, nil
+	}
+
+	if cacheErr == nil && time.Since(cacheMetadata.FetchedAt) < ipRangeSrc.CacheTTL {
+		log.Debug("using cached AWS IP ranges (sync token: ", cacheMetadata.SyncToken, ", created: ", cacheMetadata.CreateDate, ")")
+
+		return cachedIPRangeData, nil
+	}
+
+	var condMetadata *ipRangeCacheMetadata
+	if cacheErr == nil {
+		condMetadata = &cacheMetadata
+	}
+
+	jsonData, respMetadata, notModified, err := fetchRemoteIPRanges(ipRangeSrc.getURL(), condMetadata)
+	if err != nil {
+		if cacheErr == nil {
+			log.Warn("unable to refresh AWS IP ranges; falling back to stale cached copy from ", cacheMetadata.FetchedAt, ": ", err)
+
+			return cachedIPRangeData, nil
+		}
+
+		return ipRangeData, err
+	}
+
+	if notModified {
+		log.Debug("cached AWS IP ranges are still current (sync token: ", cacheMetadata.SyncToken, ")")
+
+		ipRangeData = cachedIPRangeData
+		cacheMetadata.FetchedAt = time.Now()
+		jsonData = nil
+	} else {
+		ipRangeData, err = parseIPRanges(jsonData)
+		if err != nil {
+			return ipRangeData, err
+		}
+
+		if cacheErr == nil && ipRangeData.SyncToken == cacheMetadata.SyncToken {
+			// content hasn't changed, even though the server didn't honor our conditional request
+			jsonData = nil
+		}
+
+		cacheMetadata = respMetadata
+		cacheMetadata.SyncToken = ipRangeData.SyncToken
+		cacheMetadata.CreateDate = ipRangeData.CreateDate
+		cacheMetadata.FetchedAt = time.Now()
+
+		log.Debug("fetched AWS IP ranges (sync token: ", cacheMetadata.SyncToken, ", created: ", cacheMetadata.CreateDate, ")")
+	}
+
+	if cacheDir != "" {
+		err = writeIPRangeCache(cacheDir, jsonData, cacheMetadata)
+		if err != nil {
+			log.Warn("unable to cache AWS IP ranges: ", err)
+		}
+	}
+
+	return ipRangeData, nil
+}
@@ -4,38 +4,66 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 
 	awsipprefix "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing/models/aws_ip_prefix"
 )
 
 const awsIPRangeURL string = "https://ip-ranges.amazonaws.com/ip-ranges.json"
+const genericAWSSvcName string = "AMAZON"
 
-func FetchIPRanges() (awsipprefix.RawAwsIPRangeJSON, error) {
+func parseIPRanges(jsonData []byte) (awsipprefix.RawAwsIPRangeJSON, error) {
 	var ipRangeData awsipprefix.RawAwsIPRangeJSON
 
-	// fetch IP prefixes from AWS's Public IP Range API
-	resp, err := http.Get(awsIPRangeURL)
+	jsonErr := json.Unmarshal(jsonData, &ipRangeData)
+	if jsonErr != nil {
+		return ipRangeData, jsonErr
+	}
+
+	return ipRangeData, nil
+}
+
+func fetchRemoteIPRanges(ipRangeURL string, cacheMetadata *ipRangeCacheMetadata) ([]byte, ipRangeCacheMetadata, bool, error) {
+	// fetch IP prefixes from AWS's Public IP Range API, only downloading them if they've changed since the cached copy was fetched (if any)
+	var jsonData []byte
+	var respMetadata ipRangeCacheMetadata
+
+	req, err := http.NewRequest(http.MethodGet, ipRangeURL, nil)
 	if err != nil {
-		return ipRangeData, err
-	} else if resp.StatusCode != http.StatusOK {
-		return ipRangeData, fmt.Errorf("received HTTP status %s when fetching IP ranges from remote URL :: [ URL: %s ]", resp.Status, awsIPRangeURL)
+		return jsonData, respMetadata, false, err
+	}
+
+	if cacheMetadata != nil {
+		if cacheMetadata.ETag != "" {
+			req.Header.Set("If-None-Match", cacheMetadata.ETag)
+		}
+		if cacheMetadata.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cacheMetadata.LastModified)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return jsonData, respMetadata, false, err
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
+	if resp.StatusCode == http.StatusNotModified && cacheMetadata != nil {
+		return jsonData, respMetadata, true, nil
+	} else if resp.StatusCode != http.StatusOK {
+		return jsonData, respMetadata, false, fmt.Errorf("received HTTP status %s when fetching IP ranges from remote URL :: [ URL: %s ]", resp.Status, ipRangeURL)
+	}
+
 	// I know this isn't the most efficient way to do this, but for some reason, I could not get json.Decoder() working here
-	jsonData, err := io.ReadAll(resp.Body)
+	jsonData, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return ipRangeData, err
+		return jsonData, respMetadata, false, err
 	}
 
-	jsonErr := json.Unmarshal(jsonData, &ipRangeData)
-	if jsonErr != nil {
-		return ipRangeData, jsonErr
-	}
+	respMetadata.ETag = resp.Header.Get("ETag")
+	respMetadata.LastModified = resp.Header.Get("Last-Modified")
 
-	return ipRangeData, nil
+	return jsonData, respMetadata, false, nil
 }
 
 func ConvertIPPrefixesToGeneric(ipv4Prefixes []awsipprefix.AwsIpv4Prefix, ipv6Prefixes []awsipprefix.AwsIpv6Prefix) ([]awsipprefix.GenericAWSPrefix, error) {
@@ -67,22 +95,19 @@ func ConvertIPPrefixesToGeneric(ipv4Prefixes []awsipprefix.AwsIpv4Prefix, ipv6Pr
 	return ipPrefixes, nil
 }
 
-func ResolveIPAddrToCloudSvc(ipAddr string, ipPrefixSet []awsipprefix.GenericAWSPrefix) (string, error) {
-	var cloudSvc string
-	parsedIPAddr := net.ParseIP(ipAddr)
+func SelectMostSpecificPrefix(rankedPrefixes []awsipprefix.GenericAWSPrefix) awsipprefix.GenericAWSPrefix {
+	var selectedPrefix awsipprefix.GenericAWSPrefix
 
-	for _, ipPrefix := range ipPrefixSet {
-		_, cidrNet, err := net.ParseCIDR(ipPrefix.IPRange)
-		if err != nil {
-			return cloudSvc, err
-		}
+	if len(rankedPrefixes) == 0 {
+		return selectedPrefix
+	}
 
-		if cidrNet.Contains(parsedIPAddr) {
-			// target IP is within this IP range
-			cloudSvc = ipPrefix.Service
-			break
+	// AMAZON is AWS's generic service name for ranges, so any other service is preferred, even if it's listed under a less specific prefix
+	for _, ipPrefix := range rankedPrefixes {
+		if ipPrefix.Service != genericAWSSvcName {
+			return ipPrefix
 		}
 	}
 
-	return cloudSvc, nil
+	return rankedPrefixes[0]
 }
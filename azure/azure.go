@@ -1,15 +1,15 @@
 package azure
 
 import (
-	"errors"
-	"fmt"
-
 	log "github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
-	azcdn "github.com/magneticstain/ip-2-cloudresource/azure/plugin/cdn"
-	"github.com/magneticstain/ip-2-cloudresource/azure/plugin/load_balancer"
-	virtual_machine "github.com/magneticstain/ip-2-cloudresource/azure/plugin/virtual_machines"
+
+	// service plugins register themselves with the plugin registry when imported
+	_ "github.com/magneticstain/ip-2-cloudresource/azure/plugin/cdn"
+	_ "github.com/magneticstain/ip-2-cloudresource/azure/plugin/load_balancer"
+	_ "github.com/magneticstain/ip-2-cloudresource/azure/plugin/virtual_machines"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
@@ -36,54 +36,36 @@ func ConnectToAzure() (azidentity.DefaultAzureCredential, error) {
 }
 
 func GetSupportedSvcs() []string {
-	return []string{
-		"virtual_machines",
-		"load_balancer",
-		"cdn",
-	}
+	return registry.GetSupportedSvcs("azure")
 }
 
-func (azctrlr AzureController) SearchAzureSvc(subscriptionID, ipAddr, cloudSvc string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
-	var err error
+func (azctrlr AzureController) SearchAzureSvc(subscriptionID, ipAddr, cloudSvc string) (generalResource.Resource, error) {
+	var matchingResource generalResource.Resource
 
 	log.Debug("searching ", cloudSvc, " in subscription ", subscriptionID, " using Azure controller")
 
-	switch cloudSvc {
-	case "virtual_machines":
-		azvmp := virtual_machine.AzVirtualMachinePlugin{
-			SubscriptionID: subscriptionID,
-			AzureConn:      azctrlr.AzureConn,
-		}
-
-		matchingResource, err = azvmp.SearchResources(ipAddr, matchingResource)
-		if err != nil {
-			return *matchingResource, err
-		}
-	case "load_balancer":
-		azlbp := load_balancer.AzLoadBalancerPlugin{
-			SubscriptionID: subscriptionID,
-			AzureConn:      azctrlr.AzureConn,
-		}
-
-		matchingResource, err = azlbp.SearchResources(ipAddr, matchingResource)
-		if err != nil {
-			return *matchingResource, err
-		}
-	case "cdn":
-		azcdnp := azcdn.AzCDNPlugin{
-			SubscriptionID: subscriptionID,
-			AzureConn:      azctrlr.AzureConn,
-		}
-
-		matchingResource, err = azcdnp.SearchResources(ipAddr, matchingResource)
-		if err != nil {
-			return *matchingResource, err
-		}
-	default:
-		msg := fmt.Sprintf("unknown Azure service provided: '%s'", cloudSvc)
-
-		return *matchingResource, errors.New(msg)
+	pluginConn, err := registry.NewPlugin("azure", cloudSvc, registry.PluginConfig{
+		PlatformConfig: azctrlr.AzureConn,
+		TenantID:       subscriptionID,
+	})
+	if err != nil {
+		return matchingResource, err
+	}
+
+	return pluginConn.SearchResources(ipAddr)
+}
+
+// SearchAzureSvcBulk fetches the service's inventory once and returns every resource associated with each of the IPs
+func (azctrlr AzureController) SearchAzureSvcBulk(subscriptionID string, ipAddrs []string, cloudSvc string) (map[string][]generalResource.Resource, error) {
+	log.Debug("searching ", cloudSvc, " in subscription ", subscriptionID, " using Azure controller")
+
+	pluginConn, err := registry.NewPlugin("azure", cloudSvc, registry.PluginConfig{
+		PlatformConfig: azctrlr.AzureConn,
+		TenantID:       subscriptionID,
+	})
+	if err != nil {
+		return map[string][]generalResource.Resource{}, err
 	}
 
-	return *matchingResource, nil
+	return registry.SearchResourcesBulk(pluginConn, ipAddrs)
 }
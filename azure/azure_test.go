@@ -6,7 +6,6 @@ import (
 	"testing"
 
 	azurecontroller "github.com/magneticstain/ip-2-cloudresource/azure"
-	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 func azureControllerFactory() azurecontroller.AzureController {
@@ -28,10 +27,9 @@ func TestSearchAzureSvc(t *testing.T) {
 		testName := fmt.Sprintf("%s_%s", td.cloudSvc, td.ipAddr)
 
 		ac := azureControllerFactory()
-		resource := generalResource.Resource{}
 
 		t.Run(testName, func(t *testing.T) {
-			res, _ := ac.SearchAzureSvc("", td.ipAddr, td.cloudSvc, &resource)
+			res, _ := ac.SearchAzureSvc("", td.ipAddr, td.cloudSvc)
 
 			resType := reflect.TypeOf(res)
 			expectedType := "Resource"
@@ -55,10 +53,9 @@ func TestSearchAzureSvc_UnknownCloudSvc(t *testing.T) {
 		testName := fmt.Sprintf("%s_%s", td.cloudSvc, td.ipAddr)
 
 		ac := azureControllerFactory()
-		resource := generalResource.Resource{}
 
 		t.Run(testName, func(t *testing.T) {
-			_, err := ac.SearchAzureSvc("", td.ipAddr, td.cloudSvc, &resource)
+			_, err := ac.SearchAzureSvc("", td.ipAddr, td.cloudSvc)
 			if err == nil {
 				t.Errorf("Error was expected, but not seen, when performing general Azure search; using %s for unknown cloud service name", td.cloudSvc)
 			}
@@ -8,6 +8,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/cdn/armcdn"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
@@ -17,6 +18,14 @@ type AzCDNPlugin struct {
 	SubscriptionID string
 }
 
+func init() {
+	registry.Register("azure", "cdn", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		azureConn, _ := cfg.PlatformConfig.(azidentity.DefaultAzureCredential)
+
+		return AzCDNPlugin{AzureConn: azureConn, SubscriptionID: cfg.TenantID}
+	})
+}
+
 func (azcdnp *AzCDNPlugin) ProceesCdnEndpointSet(cdnEndpointSet []*armcdn.AFDEndpoint) ([]generalResource.Resource, error) {
 	var cdnResources []generalResource.Resource
 	var currentResource generalResource.Resource
@@ -124,25 +133,17 @@ func (azcdnp *AzCDNPlugin) GetResources() ([]generalResource.Resource, error) {
 	return cdnResources, nil
 }
 
-func (azcdnp AzCDNPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+func (azcdnp AzCDNPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
 	log.Debug("fetching and searching Azure Front Door CDN resources")
 
 	fetchedResources, err := azcdnp.GetResources()
 	if err != nil {
-		return matchingResource, err
+		return map[string][]generalResource.Resource{}, err
 	}
 
-	for _, cdnResource := range fetchedResources {
-		for _, ipAddr := range cdnResource.PublicIPv4Addrs {
-			if ipAddr == tgtIP {
-				matchingResource = &cdnResource
-
-				log.Debug("IP found as Front Door CDN Endpoint -> ", matchingResource.RID)
-
-				break
-			}
-		}
-	}
+	return registry.MatchResourcesBulk(fetchedResources, tgtIPs), nil
+}
 
-	return matchingResource, nil
+func (azcdnp AzCDNPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(azcdnp, tgtIP)
 }
@@ -5,7 +5,6 @@ import (
 	"testing"
 
 	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/cdn"
-	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 func azcdnPlugFactory() plugin.AzCDNPlugin {
@@ -41,13 +40,12 @@ func TestSearchResources(t *testing.T) {
 		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
 	}
 
-	var matchingResource generalResource.Resource
 	for _, td := range tests {
 		testName := td.ipAddr
 
 		t.Run(testName, func(t *testing.T) {
-			matchedCdnEndpoint, _ := azcdnPlug.SearchResources(td.ipAddr, &matchingResource)
-			matchedCdnEndpointType := reflect.TypeOf(*matchedCdnEndpoint)
+			matchedCdnEndpoint, _ := azcdnPlug.SearchResources(td.ipAddr)
+			matchedCdnEndpointType := reflect.TypeOf(matchedCdnEndpoint)
 
 			if matchedCdnEndpointType.Name() != td.expectedType {
 				t.Errorf("Azure CDN search failed; expected %s after search, received %s", td.expectedType, matchedCdnEndpointType.Name())
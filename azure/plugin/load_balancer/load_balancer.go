@@ -8,6 +8,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	az_public_ip "github.com/magneticstain/ip-2-cloudresource/azure/public_ip"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
@@ -16,6 +17,14 @@ type AzLoadBalancerPlugin struct {
 	SubscriptionID string
 }
 
+func init() {
+	registry.Register("azure", "load_balancer", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		azureConn, _ := cfg.PlatformConfig.(azidentity.DefaultAzureCredential)
+
+		return AzLoadBalancerPlugin{AzureConn: azureConn, SubscriptionID: cfg.TenantID}
+	})
+}
+
 func (azlbp *AzLoadBalancerPlugin) GetResources() ([]generalResource.Resource, error) {
 	var lbResources []generalResource.Resource
 	var currentResource generalResource.Resource
@@ -76,25 +85,17 @@ func (azlbp *AzLoadBalancerPlugin) GetResources() ([]generalResource.Resource, e
 	return lbResources, nil
 }
 
-func (azlbp AzLoadBalancerPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+func (azlbp AzLoadBalancerPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
 	log.Debug("fetching and searching Azure load balancer resources")
 
 	fetchedResources, err := azlbp.GetResources()
 	if err != nil {
-		return matchingResource, err
+		return map[string][]generalResource.Resource{}, err
 	}
 
-	for _, lbResource := range fetchedResources {
-		for _, ipAddr := range lbResource.PublicIPv4Addrs {
-			if ipAddr == tgtIP {
-				matchingResource = &lbResource
-
-				log.Debug("IP found as Load Balancer -> ", matchingResource.RID)
-
-				break
-			}
-		}
-	}
+	return registry.MatchResourcesBulk(fetchedResources, tgtIPs), nil
+}
 
-	return matchingResource, nil
+func (azlbp AzLoadBalancerPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(azlbp, tgtIP)
 }
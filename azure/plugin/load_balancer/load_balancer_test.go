@@ -5,7 +5,6 @@ import (
 	"testing"
 
 	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/load_balancer"
-	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 func azlbPlugFactory() plugin.AzLoadBalancerPlugin {
@@ -41,13 +40,12 @@ func TestSearchResources(t *testing.T) {
 		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
 	}
 
-	var matchingResource generalResource.Resource
 	for _, td := range tests {
 		testName := td.ipAddr
 
 		t.Run(testName, func(t *testing.T) {
-			matchedLB, _ := azlbPlug.SearchResources(td.ipAddr, &matchingResource)
-			matchedLBType := reflect.TypeOf(*matchedLB)
+			matchedLB, _ := azlbPlug.SearchResources(td.ipAddr)
+			matchedLBType := reflect.TypeOf(matchedLB)
 
 			if matchedLBType.Name() != td.expectedType {
 				t.Errorf("Azure Load Balancer search failed; expected %s after search, received %s", td.expectedType, matchedLBType.Name())
@@ -10,6 +10,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	az_public_ip "github.com/magneticstain/ip-2-cloudresource/azure/public_ip"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
@@ -18,6 +19,14 @@ type AzVirtualMachinePlugin struct {
 	SubscriptionID string
 }
 
+func init() {
+	registry.Register("azure", "virtual_machines", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		azureConn, _ := cfg.PlatformConfig.(azidentity.DefaultAzureCredential)
+
+		return AzVirtualMachinePlugin{AzureConn: azureConn, SubscriptionID: cfg.TenantID}
+	})
+}
+
 func GetVMStatus(vmClient *armcompute.VirtualMachinesClient, vm *armcompute.VirtualMachine, ctx context.Context) (*string, error) {
 	var vmStatus *string
 
@@ -161,25 +170,17 @@ func (azvmp *AzVirtualMachinePlugin) GetResources() ([]generalResource.Resource,
 	return vmResources, nil
 }
 
-func (azvmp AzVirtualMachinePlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (*generalResource.Resource, error) {
+func (azvmp AzVirtualMachinePlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
 	log.Debug("fetching and searching Azure virtual machine resources")
 
 	fetchedResources, err := azvmp.GetResources()
 	if err != nil {
-		return matchingResource, err
+		return map[string][]generalResource.Resource{}, err
 	}
 
-	for _, vmResource := range fetchedResources {
-		for _, ipAddr := range vmResource.PublicIPv4Addrs {
-			if ipAddr == tgtIP {
-				matchingResource = &vmResource
-
-				log.Debug("IP found as Virtual Machine -> ", matchingResource.RID)
-
-				break
-			}
-		}
-	}
+	return registry.MatchResourcesBulk(fetchedResources, tgtIPs), nil
+}
 
-	return matchingResource, nil
+func (azvmp AzVirtualMachinePlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(azvmp, tgtIP)
 }
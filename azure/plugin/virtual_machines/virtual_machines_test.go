@@ -5,7 +5,6 @@ import (
 	"testing"
 
 	plugin "github.com/magneticstain/ip-2-cloudresource/azure/plugin/virtual_machines"
-	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 func azvmPlugFactory() plugin.AzVirtualMachinePlugin {
@@ -41,13 +40,12 @@ func TestSearchResources(t *testing.T) {
 		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
 	}
 
-	var matchingResource generalResource.Resource
 	for _, td := range tests {
 		testName := td.ipAddr
 
 		t.Run(testName, func(t *testing.T) {
-			matchedInstance, _ := azvmPlug.SearchResources(td.ipAddr, &matchingResource)
-			matchedInstanceType := reflect.TypeOf(*matchedInstance)
+			matchedInstance, _ := azvmPlug.SearchResources(td.ipAddr)
+			matchedInstanceType := reflect.TypeOf(matchedInstance)
 
 			if matchedInstanceType.Name() != td.expectedType {
 				t.Errorf("Azure Virtual Machines search failed; expected %s after search, received %s", td.expectedType, matchedInstanceType.Name())
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/magneticstain/ip-2-cloudresource/app"
+	ipfuzzing "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 )
 
 var (
@@ -18,17 +21,31 @@ var (
 	verboseOutput  bool
 	platform       string
 	ipAddr         string
+	ipAddrsFile    string
+	cidr           string
 	cloudSvc       string
 	tenantID       string
 	ipFuzzing      bool
 	advIPFuzzing   bool
 	orgSearch      bool
+	allMatches     bool
 	networkMapping bool
+	dnsRecords     bool
+	privateSearch  bool
 
 	// AWS Organization specific flags
 	orgSearchXaccountRoleARN string
 	orgSearchRoleName        string
 	orgSearchOrgUnitID       string
+
+	// AWS specific flags
+	awsRegions       string
+	ipRangesFile     string
+	ipRangesCacheTTL time.Duration
+	ipRangesOffline  bool
+	vpcID            string
+	ipamID           string
+	configAggregator string
 )
 
 var rootCmd = &cobra.Command{
@@ -36,7 +53,7 @@ var rootCmd = &cobra.Command{
 	Short:   "A tool for searching cloud resources by IP address",
 	Version: app.APP_VER,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if ipAddr == "" {
+		if ipAddr == "" && ipAddrsFile == "" && cidr == "" {
 			return fmt.Errorf("IP address is required")
 		}
 
@@ -50,6 +67,11 @@ var rootCmd = &cobra.Command{
 			log.SetLevel(log.DebugLevel)
 		}
 
+		// scoping the search to a VPC only makes sense for private IPs
+		if vpcID != "" {
+			privateSearch = true
+		}
+
 		// if the service(s) are specified, then we don't need to spend our time fuzzing the IP
 		if cloudSvc != "all" {
 			ipFuzzing = false
@@ -63,6 +85,11 @@ var rootCmd = &cobra.Command{
 			advIPFuzzing = false
 			orgSearch = false
 			networkMapping = false
+			dnsRecords = false
+			privateSearch = false
+			vpcID = ""
+			ipamID = ""
+			configAggregator = ""
 		case platform == "gcp", platform == "azure":
 			if tenantID == "" {
 				return fmt.Errorf("tenant ID is required for searching %s", strings.ToUpper(platform))
@@ -72,21 +99,33 @@ var rootCmd = &cobra.Command{
 		log.Info("starting IP-2-CloudResource")
 
 		app.InitRollbar()
-		app.WrapAndWait(app.RunCloudSearch,
-			platform,
-			tenantID,
-			ipAddr,
-			cloudSvc,
-			orgSearchXaccountRoleARN,
-			orgSearchRoleName,
-			orgSearchOrgUnitID,
-			ipFuzzing,
-			advIPFuzzing,
-			orgSearch,
-			networkMapping,
-			silentOutput,
-			jsonOutput,
-		)
+		app.WrapAndWait(app.RunCloudSearch, app.SearchOptions{
+			Platform:                 platform,
+			TenantID:                 tenantID,
+			CloudSvc:                 cloudSvc,
+			IpAddr:                   ipAddr,
+			IpAddrsFile:              ipAddrsFile,
+			CIDR:                     cidr,
+			IPFuzzing:                ipFuzzing,
+			AdvIPFuzzing:             advIPFuzzing,
+			IPRangesFile:             ipRangesFile,
+			IPRangesCacheTTL:         ipRangesCacheTTL,
+			IPRangesOffline:          ipRangesOffline,
+			OrgSearch:                orgSearch,
+			OrgSearchXaccountRoleARN: orgSearchXaccountRoleARN,
+			OrgSearchRoleName:        orgSearchRoleName,
+			OrgSearchOrgUnitID:       orgSearchOrgUnitID,
+			AWSRegions:               awsRegions,
+			AllMatches:               allMatches,
+			NetworkMapping:           networkMapping,
+			DNSRecords:               dnsRecords,
+			PrivateSearch:            privateSearch,
+			VpcID:                    vpcID,
+			IpamID:                   ipamID,
+			ConfigAggregator:         configAggregator,
+			Silent:                   silentOutput,
+			JSONOutput:               jsonOutput,
+		})
 		app.CloseRollbar()
 
 		return nil
@@ -103,22 +142,34 @@ func init() {
 	// TODO: change to separate subcommands per platform
 	rootCmd.Flags().StringVar(&platform, "platform", "aws", "Platform to target for IP search (supported values: aws, gcp, azure)")
 	rootCmd.Flags().StringVar(&ipAddr, "ipaddr", "", "IP address to search for")
+	rootCmd.Flags().StringVar(&ipAddrsFile, "ipaddr-file", "", "File of IP addresses to search for in bulk, or '-' to read from stdin; IPs can be listed one per line, as a CSV column, or as a JSON array")
 	// TODO: change to separate subcommands per service
-	rootCmd.Flags().StringVar(&cloudSvc, "svc", "all", "Specific cloud service(s) to search. Multiple services can be listed in CSV format, e.g. elbv1,elbv2. Available services are: [all, cloudfront , ec2 , elbv1 , elbv2]")
+	rootCmd.Flags().StringVar(&cidr, "cidr", "", "IPv4 or IPv6 CIDR block to search for, e.g. 203.0.113.0/24; every resource with a public IP inside the block is returned")
+	rootCmd.Flags().StringVar(&cloudSvc, "svc", "all", "Specific cloud service(s) to search, or 'all' to search every supported service for the platform. Multiple services can be listed in CSV format, e.g. elbv1,elbv2. Available services are: "+registry.FormatSupportedSvcs())
 	rootCmd.Flags().StringVar(&tenantID, "tenant-id", "", "For cloud platforms that require or support it, set this to the ID of the target tenant (e.g. project, account, subscription, etc) ID to search")
 
 	// Feature flags
 	rootCmd.Flags().BoolVar(&ipFuzzing, "ip-fuzzing", true, "Toggle the IP fuzzing feature to evaluate the IP and help optimize search (not recommended for small accounts due to overhead outweighing value)")
 	rootCmd.Flags().BoolVar(&advIPFuzzing, "adv-ip-fuzzing", true, "Toggle the advanced IP fuzzing feature to perform a more intensive heuristics evaluation to fuzz the service (not recommended for IPv6 addresses)")
+	rootCmd.Flags().StringVar(&ipRangesFile, "ip-ranges-file", "", "Path to a local copy of AWS's ip-ranges.json to use for IP fuzzing instead of fetching it from AWS")
+	rootCmd.Flags().DurationVar(&ipRangesCacheTTL, "ip-ranges-cache-ttl", ipfuzzing.DefaultIPRangeCacheTTL, "How long the locally cached copy of AWS's ip-ranges.json is used before checking AWS for changes")
+	rootCmd.Flags().BoolVar(&ipRangesOffline, "ip-ranges-offline", false, "Never fetch AWS's ip-ranges.json from AWS; only the cached copy or the file set with --ip-ranges-file is used for IP fuzzing")
 	rootCmd.Flags().BoolVar(&orgSearch, "org-search", false, "Search through all child accounts of the organization for resources, as well as target account (target account should be parent account)")
 	rootCmd.Flags().StringVar(&orgSearchXaccountRoleARN, "org-search-xaccount-role-arn", "", "The ARN of the role to assume for gathering AWS Organizations information for search, e.g. the role to assume with R/O access to your AWS Organizations account")
 	rootCmd.Flags().StringVar(&orgSearchRoleName, "org-search-role-name", "ip2cr", "The name of the role in each child account of an AWS Organization to assume when performing a search")
 	rootCmd.Flags().StringVar(&orgSearchOrgUnitID, "org-search-ou-id", "", "The ID of the AWS Organizations Organizational Unit to target when performing a search")
+	rootCmd.Flags().StringVar(&awsRegions, "regions", "", "AWS region(s) to search, in CSV format, e.g. us-east-1,eu-west-1. If not set, all regions enabled for each account are searched concurrently")
+	rootCmd.Flags().BoolVar(&allMatches, "all-matches", false, "Return every resource associated with the IP across all accounts, regions, and services, rather than stopping at the first match")
 	rootCmd.Flags().BoolVar(&networkMapping, "network-mapping", false, "If enabled, generate a network map associated with the identified resource if it's found")
 
-	if err := rootCmd.MarkFlagRequired("ipaddr"); err != nil {
-		panic(err)
-	}
+	rootCmd.Flags().BoolVar(&dnsRecords, "dns-records", false, "If enabled, look up the Route 53 A/AAAA and alias records that point at each resource that's found (AWS only)")
+	rootCmd.Flags().BoolVar(&privateSearch, "private", false, "Search for private IPs (e.g. from VPC flow logs) instead of public ones; implies --all-matches since private ranges overlap across VPCs and accounts (AWS only)")
+	rootCmd.Flags().StringVar(&vpcID, "vpc-id", "", "The ID of the VPC to limit private IP searches to; implies --private (AWS only)")
+	rootCmd.Flags().StringVar(&configAggregator, "config-aggregator", "", "The name of an AWS Config aggregator to query for network interfaces and elastic IPs across every account and region it collects from, instead of assuming a role in each account and searching every service (AWS only)")
+	rootCmd.Flags().StringVar(&ipamID, "ipam-id", "", "The ID of a VPC IPAM to resolve IPs with across every account and region it monitors; services are then only searched to add details, or for IPs the IPAM has no record of. The IPAM's home region must be the default region (AWS only)")
+
+	rootCmd.MarkFlagsOneRequired("ipaddr", "ipaddr-file", "cidr")
+	rootCmd.MarkFlagsMutuallyExclusive("ipaddr", "ipaddr-file", "cidr")
 }
 
 // Execute is exported so main.go can call from cmd package
@@ -1,62 +1,47 @@
 package gcp
 
 import (
-	"errors"
-	"fmt"
-
 	log "github.com/sirupsen/logrus"
 
-	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/cloud_sql"
-	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/compute"
-	"github.com/magneticstain/ip-2-cloudresource/gcp/plugin/load_balancing"
+	// service plugins register themselves with the plugin registry when imported
+	_ "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/cloud_sql"
+	_ "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/compute"
+	_ "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/load_balancing"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 type GCPController struct{}
 
 func GetSupportedSvcs() []string {
-	return []string{
-		"compute",
-		"load_balancing",
-		"cloud_sql",
-	}
+	return registry.GetSupportedSvcs("gcp")
 }
 
-func (gcpctrlr *GCPController) SearchGCPSvc(projectID, ipAddr, cloudSvc string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
-	var err error
+func (gcpctrlr *GCPController) SearchGCPSvc(projectID, ipAddr, cloudSvc string) (generalResource.Resource, error) {
+	var matchingResource generalResource.Resource
+
+	log.Debug("searching ", cloudSvc, " in GCP controller")
+
+	pluginConn, err := registry.NewPlugin("gcp", cloudSvc, registry.PluginConfig{
+		TenantID: projectID,
+	})
+	if err != nil {
+		return matchingResource, err
+	}
+
+	return pluginConn.SearchResources(ipAddr)
+}
 
+// SearchGCPSvcBulk fetches the service's inventory once and returns every resource associated with each of the IPs
+func (gcpctrlr *GCPController) SearchGCPSvcBulk(projectID string, ipAddrs []string, cloudSvc string) (map[string][]generalResource.Resource, error) {
 	log.Debug("searching ", cloudSvc, " in GCP controller")
 
-	switch cloudSvc {
-	case "compute":
-		comp := compute.ComputePlugin{
-			ProjectID: projectID,
-		}
-		_, err = comp.SearchResources(ipAddr, matchingResource)
-		if err != nil {
-			return *matchingResource, err
-		}
-	case "load_balancing":
-		lbp := load_balancing.LoadBalancingPlugin{
-			ProjectID: projectID,
-		}
-		_, err = lbp.SearchResources(ipAddr, matchingResource)
-		if err != nil {
-			return *matchingResource, err
-		}
-	case "cloud_sql":
-		csqlp := cloud_sql.CloudSQLPlugin{
-			ProjectID: projectID,
-		}
-		_, err = csqlp.SearchResources(ipAddr, matchingResource)
-		if err != nil {
-			return *matchingResource, err
-		}
-	default:
-		msg := fmt.Sprintf("unknown GCP service provided: '%s'", cloudSvc)
-
-		return *matchingResource, errors.New(msg)
+	pluginConn, err := registry.NewPlugin("gcp", cloudSvc, registry.PluginConfig{
+		TenantID: projectID,
+	})
+	if err != nil {
+		return map[string][]generalResource.Resource{}, err
 	}
 
-	return *matchingResource, nil
+	return registry.SearchResourcesBulk(pluginConn, ipAddrs)
 }
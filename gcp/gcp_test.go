@@ -6,7 +6,6 @@ import (
 	"testing"
 
 	gcpcontroller "github.com/magneticstain/ip-2-cloudresource/gcp"
-	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 func gcpControllerFactory() gcpcontroller.GCPController {
@@ -28,10 +27,9 @@ func TestSearchGCPSvc(t *testing.T) {
 		testName := fmt.Sprintf("%s_%s", td.cloudSvc, td.ipAddr)
 
 		ac := gcpControllerFactory()
-		resource := generalResource.Resource{}
 
 		t.Run(testName, func(t *testing.T) {
-			res, _ := ac.SearchGCPSvc("", td.ipAddr, td.cloudSvc, &resource)
+			res, _ := ac.SearchGCPSvc("", td.ipAddr, td.cloudSvc)
 
 			resType := reflect.TypeOf(res)
 			expectedType := "Resource"
@@ -55,10 +53,9 @@ func TestSearchGCPSvc_UnknownCloudSvc(t *testing.T) {
 		testName := fmt.Sprintf("%s_%s", td.cloudSvc, td.ipAddr)
 
 		ac := gcpControllerFactory()
-		resource := generalResource.Resource{}
 
 		t.Run(testName, func(t *testing.T) {
-			_, err := ac.SearchGCPSvc("", td.ipAddr, td.cloudSvc, &resource)
+			_, err := ac.SearchGCPSvc("", td.ipAddr, td.cloudSvc)
 			if err == nil {
 				t.Errorf("Error was expected, but not seen, when performing general GCP search; using %s for unknown cloud service name", td.cloudSvc)
 			}
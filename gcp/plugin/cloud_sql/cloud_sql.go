@@ -19,6 +19,7 @@ import (
 
 	"google.golang.org/api/sqladmin/v1"
 
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
@@ -27,6 +28,46 @@ type CloudSQLPlugin struct {
 	ProjectID string
 }
 
+func init() {
+	registry.Register("gcp", "cloud_sql", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		return CloudSQLPlugin{ProjectID: cfg.TenantID}
+	})
+}
+
+func FormatInstance(projectID string, csqlInstance *sqladmin.DatabaseInstance) (generalResource.Resource, error) {
+	instanceName := csqlInstance.Name
+	instanceStatus := csqlInstance.State
+
+	log.Debug("cloudsql instance found - Name: ", instanceName, ", Status: ", instanceStatus)
+
+	currentResource := generalResource.Resource{
+		Name:           instanceName,
+		Status:         instanceStatus,
+		CloudSvc:       "cloud_sql",
+		AccountAliases: []string{projectID},
+	}
+
+	for _, ipAddrMap := range csqlInstance.IpAddresses {
+		ipAddr := ipAddrMap.IpAddress
+
+		ipVer, err := utils.DetermineIpAddrVersion(ipAddr)
+		if err != nil {
+			return currentResource, err
+		}
+
+		switch ipVer {
+		case 4:
+			currentResource.PublicIPv4Addrs = append(currentResource.PublicIPv4Addrs, ipAddr)
+		case 6:
+			currentResource.PublicIPv6Addrs = append(currentResource.PublicIPv6Addrs, ipAddr)
+		default:
+			return currentResource, fmt.Errorf("invalid IP version found for GCP CloudSQL instance; IP: %s, Version: IPv%d", ipAddr, ipVer)
+		}
+	}
+
+	return currentResource, nil
+}
+
 func (csqlp CloudSQLPlugin) GetResources() ([]generalResource.Resource, error) {
 	var csqlResources []generalResource.Resource
 
@@ -44,35 +85,9 @@ func (csqlp CloudSQLPlugin) GetResources() ([]generalResource.Resource, error) {
 	}
 
 	for _, csqlInstance := range csqlInstListResp.Items {
-		instanceName := csqlInstance.Name
-		instanceStatus := csqlInstance.State
-		instanceIpAddrs := csqlInstance.IpAddresses
-
-		log.Debug("load balancer endpoint found - Name: ", instanceName, ", Status: ", instanceStatus)
-
-		currentResource := generalResource.Resource{
-			Name:           instanceName,
-			Status:         instanceStatus,
-			CloudSvc:       "cloudsql",
-			AccountAliases: []string{csqlp.ProjectID},
-		}
-
-		for _, ipAddrMap := range instanceIpAddrs {
-			ipAddr := ipAddrMap.IpAddress
-
-			ipVer, err := utils.DetermineIpAddrVersion(ipAddr)
-			if err != nil {
-				return csqlResources, err
-			}
-
-			switch ipVer {
-			case 4:
-				currentResource.PublicIPv4Addrs = append(currentResource.PublicIPv4Addrs, ipAddr)
-			case 6:
-				currentResource.PublicIPv6Addrs = append(currentResource.PublicIPv6Addrs, ipAddr)
-			default:
-				return csqlResources, fmt.Errorf("invalid IP version found for GCP CloudSQL instance; IP: %s, Version: IPv%d", ipAddr, ipVer)
-			}
+		currentResource, err := FormatInstance(csqlp.ProjectID, csqlInstance)
+		if err != nil {
+			return csqlResources, err
 		}
 
 		csqlResources = append(
@@ -84,41 +99,21 @@ func (csqlp CloudSQLPlugin) GetResources() ([]generalResource.Resource, error) {
 	return csqlResources, nil
 }
 
-func (csqlp CloudSQLPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
+func (csqlp CloudSQLPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
 	log.Debug("fetching and searching cloudsql resources")
 
 	fetchedResources, err := csqlp.GetResources()
 	if err != nil {
-		return *matchingResource, err
+		return map[string][]generalResource.Resource{}, err
 	}
 
-	for _, csqlResource := range fetchedResources {
-		ridSlug := fmt.Sprintf("%s/%s", csqlResource.Id, csqlResource.Name)
-
-		for _, ipv4Addr := range csqlResource.PublicIPv4Addrs {
-			if ipv4Addr == tgtIP {
-				matchingResource.RID = ridSlug
-				matchingResource.CloudSvc = "cloud_sql"
-
-				break
-			}
-		}
-
-		for _, ipv6Addr := range csqlResource.PublicIPv6Addrs {
-			if ipv6Addr == tgtIP {
-				matchingResource.RID = ridSlug
-				matchingResource.CloudSvc = "cloud_sql"
-
-				break
-			}
-		}
-
-		if matchingResource.RID != "" {
-			log.Debug("IP found as CloudSQL instance -> ", matchingResource.RID)
-
-			break
-		}
+	for i, csqlResource := range fetchedResources {
+		fetchedResources[i].RID = fmt.Sprintf("%s/%s", csqlResource.Id, csqlResource.Name)
 	}
 
-	return *matchingResource, nil
+	return registry.MatchResourcesBulk(fetchedResources, tgtIPs), nil
+}
+
+func (csqlp CloudSQLPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(csqlp, tgtIP)
 }
@@ -4,7 +4,10 @@ import (
 	"reflect"
 	"testing"
 
+	"google.golang.org/api/sqladmin/v1"
+
 	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/cloud_sql"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
@@ -14,6 +17,54 @@ func csqlPlugFactory() plugin.CloudSQLPlugin {
 	return csqlPlug
 }
 
+func TestFormatInstance(t *testing.T) {
+	var tests = []struct {
+		name, tgtIP string
+		ipAddrs     []string
+		match       bool
+	}{
+		{"IPv4Match", "34.1.2.3", []string{"34.1.2.3"}, true},
+		{"IPv6Match", "2600:1900:4000::1", []string{"34.1.2.3", "2600:1900:4000::1"}, true},
+		{"NoMatch", "34.1.2.4", []string{"34.1.2.3"}, false},
+	}
+
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			csqlInstance := &sqladmin.DatabaseInstance{Name: "test-db", State: "RUNNABLE"}
+			for _, ipAddr := range td.ipAddrs {
+				csqlInstance.IpAddresses = append(csqlInstance.IpAddresses, &sqladmin.IpMapping{IpAddress: ipAddr})
+			}
+
+			csqlResource, err := plugin.FormatInstance("test-project", csqlInstance)
+			if err != nil {
+				t.Fatalf("formatting GCP CloudSQL instance failed; error: %s", err)
+			}
+
+			matches := registry.MatchResourcesBulk([]generalResource.Resource{csqlResource}, []string{td.tgtIP})[td.tgtIP]
+			if (len(matches) > 0) != td.match {
+				t.Fatalf("GCP CloudSQL bulk match failed; IP: %s, Found: %t, Should Be Found?: %t", td.tgtIP, len(matches) > 0, td.match)
+			}
+
+			for _, matchedResource := range matches {
+				if matchedResource.CloudSvc != "cloud_sql" {
+					t.Errorf("GCP CloudSQL bulk match reported wrong cloud service; expected cloud_sql, received %s", matchedResource.CloudSvc)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatInstance_InvalidIP(t *testing.T) {
+	csqlInstance := &sqladmin.DatabaseInstance{
+		Name:        "test-db",
+		IpAddresses: []*sqladmin.IpMapping{{IpAddress: "1234.45.9666.1"}},
+	}
+
+	if _, err := plugin.FormatInstance("test-project", csqlInstance); err == nil {
+		t.Errorf("formatting GCP CloudSQL instance with an invalid IP should fail")
+	}
+}
+
 func TestGetResources(t *testing.T) {
 	csqlPlug := csqlPlugFactory()
 
@@ -41,12 +92,11 @@ func TestSearchResources(t *testing.T) {
 		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
 	}
 
-	var matchingResource generalResource.Resource
 	for _, td := range tests {
 		testName := td.ipAddr
 
 		t.Run(testName, func(t *testing.T) {
-			matchedInstance, _ := csqlPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedInstance, _ := csqlPlug.SearchResources(td.ipAddr)
 			matchedInstanceType := reflect.TypeOf(matchedInstance)
 
 			if matchedInstanceType.Name() != td.expectedType {
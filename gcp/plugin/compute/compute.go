@@ -11,6 +11,7 @@ import (
 	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
 	"google.golang.org/api/iterator"
 
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
@@ -18,31 +19,10 @@ type ComputePlugin struct {
 	ProjectID string
 }
 
-func CheckComputeIP(computeResource, matchingResource *generalResource.Resource, tgtIp string, ipVer int) (*generalResource.Resource, bool) {
-	var ipSet []string
-	var found bool
-
-	log.Debug("checking target IP [ ", tgtIp, "] against IPv", ipVer, " resource address set")
-
-	switch ipVer {
-	case 4:
-		ipSet = computeResource.PublicIPv4Addrs
-	case 6:
-		ipSet = computeResource.PublicIPv6Addrs
-	}
-
-	for _, ipAddr := range ipSet {
-		if ipAddr == tgtIp {
-			matchingResource.RID = fmt.Sprintf("%s/%s/%s", computeResource.AccountID, computeResource.Id, computeResource.Name)
-			matchingResource.CloudSvc = "compute"
-
-			found = true
-
-			break
-		}
-	}
-
-	return matchingResource, found
+func init() {
+	registry.Register("gcp", "compute", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		return ComputePlugin{ProjectID: cfg.TenantID}
+	})
 }
 
 func GetPublicIPAddrsFromInstance(computeInstance *gcpcomputepbapi.Instance) ([]string, []string) {
@@ -123,30 +103,21 @@ func (comp ComputePlugin) GetResources() ([]generalResource.Resource, error) {
 	return computeResources, nil
 }
 
-func (comp ComputePlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
+func (comp ComputePlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
 	log.Debug("fetching and searching compute resources")
 
 	fetchedResources, err := comp.GetResources()
 	if err != nil {
-		return *matchingResource, err
+		return map[string][]generalResource.Resource{}, err
 	}
 
-	var found bool
-	for _, computeResource := range fetchedResources {
-		// IPv4 is checked first
-		matchingResource, found = CheckComputeIP(&computeResource, matchingResource, tgtIP, 4)
-
-		if !found {
-			// check IPv6 next
-			matchingResource, found = CheckComputeIP(&computeResource, matchingResource, tgtIP, 6)
-		}
-
-		if found {
-			log.Debug("IP found as Compute VM -> ", matchingResource.RID)
-
-			break
-		}
+	for i, computeResource := range fetchedResources {
+		fetchedResources[i].RID = fmt.Sprintf("%s/%s/%s", computeResource.AccountID, computeResource.Id, computeResource.Name)
 	}
 
-	return *matchingResource, nil
+	return registry.MatchResourcesBulk(fetchedResources, tgtIPs), nil
+}
+
+func (comp ComputePlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(comp, tgtIP)
 }
@@ -4,7 +4,11 @@ import (
 	"reflect"
 	"testing"
 
+	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
+	"google.golang.org/protobuf/proto"
+
 	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/compute"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
@@ -14,82 +18,44 @@ func compPlugFactory() plugin.ComputePlugin {
 	return compPlug
 }
 
-func TestCheckComputeIP_ValidIPv4s(t *testing.T) {
+func TestGetPublicIPAddrsFromInstance(t *testing.T) {
 	var tests = []struct {
-		ipAddr, tgtIp string
+		name, tgtIP   string
+		natIP, extIP6 string
 		match         bool
 	}{
-		{"1.1.1.1", "1.1.1.1", true},
-		{"18.161.22.61", "18.161.22.61", true},
-		{"192.168.1.1", "192.168.1.1", true},
-		{"22.642.22.19", "2222.642.22.1999", false},
+		{"IPv4Match", "34.1.2.3", "34.1.2.3", "", true},
+		{"IPv6Match", "2600:1900:4000::1", "34.1.2.3", "2600:1900:4000::1", true},
+		{"IPv4NoMatch", "34.1.2.4", "34.1.2.3", "", false},
+		{"IPv6NoMatch", "2600:1900:4000::2", "", "2600:1900:4000::1", false},
 	}
 
 	for _, td := range tests {
-		testName := td.ipAddr
-
-		t.Run(testName, func(t *testing.T) {
-			var computeResource, matchingResource generalResource.Resource
-
-			computeResource.PublicIPv4Addrs = []string{td.ipAddr}
-
-			_, found := plugin.CheckComputeIP(&computeResource, &matchingResource, td.tgtIp, 4)
-
-			if found != td.match {
-				t.Errorf("Running Compute IP check for IPv4 addresses failed; IP: %s, Found: %t, Should Be Found?: %t", td.tgtIp, found, td.match)
+		t.Run(td.name, func(t *testing.T) {
+			accessConfig := &gcpcomputepbapi.AccessConfig{}
+			if td.natIP != "" {
+				accessConfig.NatIP = proto.String(td.natIP)
 			}
-		})
-	}
-}
-
-func TestCheckComputeIP_IPv6(t *testing.T) {
-	var tests = []struct {
-		ipAddr, tgtIp string
-		match         bool
-	}{
-		{"2600:9000:24eb:dc00:1:3b80:4f00:21", "2600:9000:24eb:dc00:1:3b80:4f00:21", true},
-		{"2001:db8:3333:4444:5555:6666:7777:8888", "2001:db8:3333:4444:5555:6666:7777:8888", true},
-		{"xxxx:yyyy:3333:4444:5555:6666:7777:8888", "1111:2222:3333:4444:5555:6666:7777:8888", false},
-	}
-
-	for _, td := range tests {
-		testName := td.ipAddr
-
-		t.Run(testName, func(t *testing.T) {
-			var computeResource, matchingResource generalResource.Resource
-
-			computeResource.PublicIPv6Addrs = []string{td.ipAddr}
-
-			_, found := plugin.CheckComputeIP(&computeResource, &matchingResource, td.tgtIp, 6)
-
-			if found != td.match {
-				t.Errorf("Running Compute IP check for IPv6 addresses failed; IP: %s, Found: %t, Should Be Found?: %t", td.tgtIp, found, td.match)
+			if td.extIP6 != "" {
+				accessConfig.ExternalIpv6 = proto.String(td.extIP6)
 			}
-		})
-	}
-}
 
-func TestCheckComputeIP_InvalidIPs(t *testing.T) {
-	var tests = []struct {
-		ipAddr, tgtIp string
-		match         bool
-	}{
-		{"1234.56.78.910", "1234.56.78.910", true},
-		{"1111:2222:3333:4444:5555:6666:7777:8888", "xxxx:yyyy:3333:4444:5555:6666:7777:8888", false},
-	}
-
-	for _, td := range tests {
-		testName := td.ipAddr
-
-		t.Run(testName, func(t *testing.T) {
-			var computeResource, matchingResource generalResource.Resource
-
-			computeResource.PublicIPv6Addrs = []string{td.ipAddr}
+			computeInstance := &gcpcomputepbapi.Instance{
+				NetworkInterfaces: []*gcpcomputepbapi.NetworkInterface{
+					{AccessConfigs: []*gcpcomputepbapi.AccessConfig{accessConfig}},
+				},
+			}
 
-			_, found := plugin.CheckComputeIP(&computeResource, &matchingResource, td.tgtIp, 6)
+			publicIPv4Addrs, publicIPv6Addrs := plugin.GetPublicIPAddrsFromInstance(computeInstance)
+			computeResource := generalResource.Resource{
+				CloudSvc:        "compute",
+				PublicIPv4Addrs: publicIPv4Addrs,
+				PublicIPv6Addrs: publicIPv6Addrs,
+			}
 
-			if found != td.match {
-				t.Errorf("Running Compute IP check for IPv6 addresses failed; IP: %s, Found: %t, Should Be Found?: %t", td.tgtIp, found, td.match)
+			matches := registry.MatchResourcesBulk([]generalResource.Resource{computeResource}, []string{td.tgtIP})[td.tgtIP]
+			if (len(matches) > 0) != td.match {
+				t.Errorf("GCP Compute bulk match failed; IP: %s, Found: %t, Should Be Found?: %t", td.tgtIP, len(matches) > 0, td.match)
 			}
 		})
 	}
@@ -122,12 +88,11 @@ func TestSearchResources(t *testing.T) {
 		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
 	}
 
-	var matchingResource generalResource.Resource
 	for _, td := range tests {
 		testName := td.ipAddr
 
 		t.Run(testName, func(t *testing.T) {
-			matchedInstance, _ := compPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedInstance, _ := compPlug.SearchResources(td.ipAddr)
 			matchedInstanceType := reflect.TypeOf(matchedInstance)
 
 			if matchedInstanceType.Name() != td.expectedType {
@@ -11,6 +11,7 @@ import (
 	gcpcomputepbapi "cloud.google.com/go/compute/apiv1/computepb"
 	"google.golang.org/api/iterator"
 
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
@@ -19,6 +20,12 @@ type LoadBalancingPlugin struct {
 	ProjectID string
 }
 
+func init() {
+	registry.Register("gcp", "load_balancing", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		return LoadBalancingPlugin{ProjectID: cfg.TenantID}
+	})
+}
+
 func (lbp LoadBalancingPlugin) GetResources() ([]generalResource.Resource, error) {
 	var gaClient *gcpcomputeapi.GlobalAddressesClient
 	var lbGlobalAddrList *gcpcomputeapi.AddressIterator
@@ -86,41 +93,21 @@ func (lbp LoadBalancingPlugin) GetResources() ([]generalResource.Resource, error
 	return lbResources, nil
 }
 
-func (lbp LoadBalancingPlugin) SearchResources(tgtIP string, matchingResource *generalResource.Resource) (generalResource.Resource, error) {
+func (lbp LoadBalancingPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
 	log.Debug("fetching and searching load balancing resources")
 
 	fetchedResources, err := lbp.GetResources()
 	if err != nil {
-		return *matchingResource, err
+		return map[string][]generalResource.Resource{}, err
 	}
 
-	for _, lbResource := range fetchedResources {
-		ridSlug := fmt.Sprintf("%s/%s", lbResource.Id, lbResource.Name)
-
-		for _, ipv4Addr := range lbResource.PublicIPv4Addrs {
-			if ipv4Addr == tgtIP {
-				matchingResource.RID = ridSlug
-				matchingResource.CloudSvc = "load_balancing"
-
-				break
-			}
-		}
-
-		for _, ipv6Addr := range lbResource.PublicIPv6Addrs {
-			if ipv6Addr == tgtIP {
-				matchingResource.RID = ridSlug
-				matchingResource.CloudSvc = "load_balancing"
-
-				break
-			}
-		}
-
-		if matchingResource.RID != "" {
-			log.Debug("IP found as Load Balancer -> ", matchingResource.RID)
-
-			break
-		}
+	for i, lbResource := range fetchedResources {
+		fetchedResources[i].RID = fmt.Sprintf("%s/%s", lbResource.Id, lbResource.Name)
 	}
 
-	return *matchingResource, nil
+	return registry.MatchResourcesBulk(fetchedResources, tgtIPs), nil
+}
+
+func (lbp LoadBalancingPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return registry.FirstMatch(lbp, tgtIP)
 }
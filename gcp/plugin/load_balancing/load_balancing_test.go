@@ -5,7 +5,6 @@ import (
 	"testing"
 
 	plugin "github.com/magneticstain/ip-2-cloudresource/gcp/plugin/load_balancing"
-	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 )
 
 func lbPlugFactory() plugin.LoadBalancingPlugin {
@@ -41,12 +40,11 @@ func TestSearchResources(t *testing.T) {
 		{"x2600:9000:24eb:XYZ1:1:3b80:4f00:21", "Resource"},
 	}
 
-	var matchingResource generalResource.Resource
 	for _, td := range tests {
 		testName := td.ipAddr
 
 		t.Run(testName, func(t *testing.T) {
-			matchedInstance, _ := lbPlug.SearchResources(td.ipAddr, &matchingResource)
+			matchedInstance, _ := lbPlug.SearchResources(td.ipAddr)
 			matchedInstanceType := reflect.TypeOf(matchedInstance)
 
 			if matchedInstanceType.Name() != td.expectedType {
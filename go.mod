@@ -11,16 +11,27 @@ require (
 	github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork v1.1.0
 	github.com/aws/aws-sdk-go-v2 v1.40.0
 	github.com/aws/aws-sdk-go-v2/config v1.32.2
+	github.com/aws/aws-sdk-go-v2/service/apigateway v1.38.1
+	github.com/aws/aws-sdk-go-v2/service/apigatewayv2 v1.33.2
+	github.com/aws/aws-sdk-go-v2/service/configservice v1.59.6
 	github.com/aws/aws-sdk-go-v2/service/ec2 v1.274.0
+	github.com/aws/aws-sdk-go-v2/service/ecs v1.69.1
+	github.com/aws/aws-sdk-go-v2/service/eks v1.76.0
 	github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing v1.33.15
 	github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2 v1.54.1
+	github.com/aws/aws-sdk-go-v2/service/globalaccelerator v1.34.5
 	github.com/aws/aws-sdk-go-v2/service/iam v1.52.1
+	github.com/aws/aws-sdk-go-v2/service/lightsail v1.50.8
 	github.com/aws/aws-sdk-go-v2/service/organizations v1.48.0
+	github.com/aws/aws-sdk-go-v2/service/rds v1.111.1
+	github.com/aws/aws-sdk-go-v2/service/redshift v1.61.1
+	github.com/aws/aws-sdk-go-v2/service/route53 v1.61.0
 	github.com/rollbar/rollbar-go v1.4.8
 	github.com/sirupsen/logrus v1.9.3
 	github.com/spf13/cobra v1.10.1
 	golang.org/x/exp v0.0.0-20240325151524-a685a6edb6d8
 	google.golang.org/api v0.256.0
+	google.golang.org/protobuf v1.36.10
 )
 
 require (
@@ -57,7 +68,6 @@ require (
 	google.golang.org/genproto/googleapis/api v0.0.0-20250818200422-3122310a409c // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251103181224-f26f9409b101 // indirect
 	google.golang.org/grpc v1.76.0 // indirect
-	google.golang.org/protobuf v1.36.10 // indirect
 )
 
 require (
@@ -0,0 +1,235 @@
+package registry
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
+// SearchPlugin is the common interface implemented by every cloud service plugin, regardless of platform
+type SearchPlugin interface {
+	SearchResources(tgtIP string) (generalResource.Resource, error)
+}
+
+// BulkSearchPlugin can optionally be implemented by plugins that can match many IPs against a single fetch of the service's inventory; every matching resource is returned for each IP, since a single IP can legitimately map to several resources (e.g. CloudFront edge IPs shared by many distributions)
+type BulkSearchPlugin interface {
+	SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error)
+}
+
+// PluginConfig holds the platform connection and search options that plugin factories can pull from
+type PluginConfig struct {
+	// PlatformConfig holds whatever the platform's controller passes to its plugins, e.g. the connection and any platform-specific search options; each platform's plugins assert it to the type their controller uses
+	PlatformConfig any
+	TenantID       string
+	NetworkMapping bool
+}
+
+type PluginFactory func(cfg PluginConfig) SearchPlugin
+
+// Capability flags optional behaviour of a service; plugins declare them when registering, so the registry can answer questions about a service without generating a plugin for it
+type Capability int
+
+const (
+	// Global services aren't bound to a single region (e.g. CloudFront), so controllers know to only search them once
+	Global Capability = iota
+	// Fallback services attribute IPs generically (e.g. via ENIs), so they're searched after every service-specific plugin
+	Fallback
+	// PrivateSearch services can match the private IPs of resources within a VPC, so controllers know which services can be searched in private mode
+	PrivateSearch
+)
+
+type registeredPlugin struct {
+	factory      PluginFactory
+	capabilities []Capability
+}
+
+var (
+	registryLock sync.RWMutex
+	plugins      = map[string]map[string]registeredPlugin{}
+)
+
+// Register adds a plugin factory for the given platform and service, along with any optional capabilities the service has; plugins should call this from their init() function
+func Register(platform, cloudSvc string, factory PluginFactory, capabilities ...Capability) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	platform = strings.ToLower(platform)
+	cloudSvc = strings.ToLower(cloudSvc)
+
+	if _, found := plugins[platform]; !found {
+		plugins[platform] = map[string]registeredPlugin{}
+	}
+
+	if _, found := plugins[platform][cloudSvc]; found {
+		panic(fmt.Sprintf("plugin already registered for %s service '%s'", platform, cloudSvc))
+	}
+
+	plugins[platform][cloudSvc] = registeredPlugin{factory: factory, capabilities: capabilities}
+}
+
+func GetSupportedPlatforms() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	var platforms []string
+	for platform := range plugins {
+		platforms = append(platforms, platform)
+	}
+	slices.Sort(platforms)
+
+	return platforms
+}
+
+func GetSupportedSvcs(platform string) []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	var cloudSvcs []string
+	for cloudSvc := range plugins[strings.ToLower(platform)] {
+		cloudSvcs = append(cloudSvcs, cloudSvc)
+	}
+	slices.Sort(cloudSvcs)
+
+	return cloudSvcs
+}
+
+// PrioritizeSvcs orders the services so that fallback plugins are searched last, since service-specific plugins provide richer results for the same IP
+func PrioritizeSvcs(platform string, cloudSvcs []string) []string {
+	prioritizedSvcs := slices.Clone(cloudSvcs)
+
+	slices.SortStableFunc(prioritizedSvcs, func(a, b string) int {
+		return cmp.Compare(isFallbackSvc(platform, a), isFallbackSvc(platform, b))
+	})
+
+	return prioritizedSvcs
+}
+
+func hasCapability(platform, cloudSvc string, capability Capability) bool {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	plugin, found := plugins[strings.ToLower(platform)][strings.ToLower(cloudSvc)]
+
+	return found && slices.Contains(plugin.capabilities, capability)
+}
+
+func isFallbackSvc(platform, cloudSvc string) int {
+	if hasCapability(platform, cloudSvc, Fallback) {
+		return 1
+	}
+
+	return 0
+}
+
+// IsGlobalSvc reports whether the service isn't bound to a single region, i.e. only needs to be searched once
+func IsGlobalSvc(platform, cloudSvc string) bool {
+	return hasCapability(platform, cloudSvc, Global)
+}
+
+func SupportsPrivateSearch(platform, cloudSvc string) bool {
+	return hasCapability(platform, cloudSvc, PrivateSearch)
+}
+
+// GetPrivateSearchSvcs returns the services that can be searched for private IPs on the given platform
+func GetPrivateSearchSvcs(platform string) []string {
+	var cloudSvcs []string
+	for _, cloudSvc := range GetSupportedSvcs(platform) {
+		if SupportsPrivateSearch(platform, cloudSvc) {
+			cloudSvcs = append(cloudSvcs, cloudSvc)
+		}
+	}
+
+	return cloudSvcs
+}
+
+func IsSupportedSvc(platform, cloudSvc string) bool {
+	return slices.Contains(GetSupportedSvcs(platform), strings.ToLower(cloudSvc))
+}
+
+// NewPlugin generates a ready-to-use search plugin for the given platform and service
+func NewPlugin(platform, cloudSvc string, cfg PluginConfig) (SearchPlugin, error) {
+	registryLock.RLock()
+	plugin, found := plugins[strings.ToLower(platform)][strings.ToLower(cloudSvc)]
+	registryLock.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("unknown %s service provided: '%s'", strings.ToUpper(platform), cloudSvc)
+	}
+
+	return plugin.factory(cfg), nil
+}
+
+// SearchResourcesBulk returns every resource the plugin can find matching each of the IPs, falling back to searching the IPs one at a time if the plugin doesn't support bulk searches
+func SearchResourcesBulk(plugin SearchPlugin, tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	if bulkSearchPlugin, ok := plugin.(BulkSearchPlugin); ok {
+		return bulkSearchPlugin.SearchResourcesBulk(tgtIPs)
+	}
+
+	matchingResources := map[string][]generalResource.Resource{}
+	for _, tgtIP := range tgtIPs {
+		matchingResource, err := plugin.SearchResources(tgtIP)
+		if err != nil {
+			return matchingResources, err
+		}
+
+		if matchingResource.RID != "" {
+			matchingResources[tgtIP] = append(matchingResources[tgtIP], matchingResource)
+		}
+	}
+
+	return matchingResources, nil
+}
+
+// SearchAllResources returns every resource the plugin can find matching the IP
+func SearchAllResources(plugin SearchPlugin, tgtIP string) ([]generalResource.Resource, error) {
+	matchingResources, err := SearchResourcesBulk(plugin, []string{tgtIP})
+
+	return matchingResources[tgtIP], err
+}
+
+// MatchResourcesBulk matches resources that already have their public IPs populated against each of the search targets, which can be IPs or CIDR blocks
+func MatchResourcesBulk(resources []generalResource.Resource, tgtIPs []string) map[string][]generalResource.Resource {
+	matchingResources := map[string][]generalResource.Resource{}
+	ipMatcher := utils.NewIPMatcher(tgtIPs)
+
+	for _, resource := range resources {
+		resourceIPAddrs := slices.Concat(resource.PublicIPv4Addrs, resource.PublicIPv6Addrs)
+
+		for _, tgt := range ipMatcher.MatchAny(resourceIPAddrs) {
+			matchingResources[tgt] = append(matchingResources[tgt], resource)
+		}
+	}
+
+	return matchingResources
+}
+
+// FirstMatch allows bulk-capable plugins to implement SearchResources on top of their bulk search
+func FirstMatch(plugin BulkSearchPlugin, tgtIP string) (generalResource.Resource, error) {
+	var matchingResource generalResource.Resource
+
+	matchingResources, err := plugin.SearchResourcesBulk([]string{tgtIP})
+	if err != nil {
+		return matchingResource, err
+	}
+
+	if len(matchingResources[tgtIP]) > 0 {
+		matchingResource = matchingResources[tgtIP][0]
+	}
+
+	return matchingResource, nil
+}
+
+// FormatSupportedSvcs generates a human-readable summary of every registered service, grouped by platform
+func FormatSupportedSvcs() string {
+	var platformSlugs []string
+	for _, platform := range GetSupportedPlatforms() {
+		platformSlugs = append(platformSlugs, fmt.Sprintf("%s: [%s]", platform, strings.Join(GetSupportedSvcs(platform), ", ")))
+	}
+
+	return strings.Join(platformSlugs, "; ")
+}
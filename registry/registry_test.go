@@ -0,0 +1,278 @@
+package registry_test
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+type mockPlugin struct {
+	cloudSvc string
+}
+
+func (mp mockPlugin) SearchResources(tgtIP string) (generalResource.Resource, error) {
+	return generalResource.Resource{RID: tgtIP, CloudSvc: mp.cloudSvc}, nil
+}
+
+func init() {
+	for _, svc := range []string{"svc_b", "svc_a"} {
+		registry.Register("mock", svc, func(cfg registry.PluginConfig) registry.SearchPlugin {
+			return mockPlugin{cloudSvc: svc}
+		})
+	}
+
+	registry.Register("mock_fallback", "svc_a", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		return mockPlugin{cloudSvc: "svc_a"}
+	}, registry.Fallback)
+	registry.Register("mock_fallback", "svc_b", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		return mockPlugin{cloudSvc: "svc_b"}
+	})
+
+	registry.Register("mock_private", "svc_a", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		return mockPlugin{cloudSvc: "svc_a"}
+	})
+	registry.Register("mock_private", "svc_b", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		return mockPlugin{cloudSvc: "svc_b"}
+	}, registry.PrivateSearch, registry.Global)
+}
+
+func TestGetSupportedSvcs(t *testing.T) {
+	var tests = []struct {
+		platform            string
+		expectedCloudSvcSet []string
+	}{
+		{"mock", []string{"svc_a", "svc_b"}},
+		{"MOCK", []string{"svc_a", "svc_b"}},
+		{"not_a_platform", nil},
+	}
+
+	for _, td := range tests {
+		testName := td.platform
+
+		t.Run(testName, func(t *testing.T) {
+			res := registry.GetSupportedSvcs(td.platform)
+
+			if !slices.Equal(res, td.expectedCloudSvcSet) {
+				t.Errorf("Fetching supported services from registry failed; expected %v, received %v", td.expectedCloudSvcSet, res)
+			}
+		})
+	}
+}
+
+func TestIsSupportedSvc(t *testing.T) {
+	var tests = []struct {
+		platform, cloudSvc string
+		supported          bool
+	}{
+		{"mock", "svc_a", true},
+		{"mock", "SVC_B", true},
+		{"mock", "svc_c", false},
+		{"not_a_platform", "svc_a", false},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("%s_%s", td.platform, td.cloudSvc)
+
+		t.Run(testName, func(t *testing.T) {
+			if registry.IsSupportedSvc(td.platform, td.cloudSvc) != td.supported {
+				t.Errorf("Service support check failed; expected %t for %s service %s", td.supported, td.platform, td.cloudSvc)
+			}
+		})
+	}
+}
+
+func TestGetPrivateSearchSvcs(t *testing.T) {
+	var tests = []struct {
+		platform            string
+		expectedCloudSvcSet []string
+	}{
+		{"mock_private", []string{"svc_b"}},
+		{"mock", nil},
+		{"not_a_platform", nil},
+	}
+
+	for _, td := range tests {
+		testName := td.platform
+
+		t.Run(testName, func(t *testing.T) {
+			res := registry.GetPrivateSearchSvcs(td.platform)
+
+			if !slices.Equal(res, td.expectedCloudSvcSet) {
+				t.Errorf("Fetching private search services from registry failed; expected %v, received %v", td.expectedCloudSvcSet, res)
+			}
+		})
+	}
+}
+
+func TestIsGlobalSvc(t *testing.T) {
+	var tests = []struct {
+		platform, cloudSvc string
+		global             bool
+	}{
+		{"mock_private", "svc_b", true},
+		{"MOCK_PRIVATE", "SVC_B", true},
+		{"mock_private", "svc_a", false},
+		{"mock", "svc_c", false},
+		{"not_a_platform", "svc_b", false},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("%s_%s", td.platform, td.cloudSvc)
+
+		t.Run(testName, func(t *testing.T) {
+			if registry.IsGlobalSvc(td.platform, td.cloudSvc) != td.global {
+				t.Errorf("Global service check failed; expected %t for %s service %s", td.global, td.platform, td.cloudSvc)
+			}
+		})
+	}
+}
+
+func TestPrioritizeSvcs(t *testing.T) {
+	var tests = []struct {
+		platform            string
+		cloudSvcs           []string
+		expectedCloudSvcSet []string
+	}{
+		{"mock", []string{"svc_a", "svc_b"}, []string{"svc_a", "svc_b"}},
+		{"mock_fallback", []string{"svc_a", "svc_b"}, []string{"svc_b", "svc_a"}},
+		{"mock_fallback", []string{"svc_a", "not_a_svc", "svc_b"}, []string{"not_a_svc", "svc_b", "svc_a"}},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("%s_%v", td.platform, td.cloudSvcs)
+
+		t.Run(testName, func(t *testing.T) {
+			res := registry.PrioritizeSvcs(td.platform, td.cloudSvcs)
+
+			if !slices.Equal(res, td.expectedCloudSvcSet) {
+				t.Errorf("Prioritizing services failed; expected %v, received %v", td.expectedCloudSvcSet, res)
+			}
+		})
+	}
+}
+
+func TestNewPlugin(t *testing.T) {
+	searchPlugin, err := registry.NewPlugin("mock", "svc_a", registry.PluginConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error received when generating plugin from registry: %s", err)
+	}
+
+	res, _ := searchPlugin.SearchResources("1.1.1.1")
+	if res.CloudSvc != "svc_a" || res.RID != "1.1.1.1" {
+		t.Errorf("Plugin generated from registry returned unexpected resource; received %+v", res)
+	}
+}
+
+func TestNewPlugin_UnknownCloudSvc(t *testing.T) {
+	var tests = []struct {
+		platform, cloudSvc string
+	}{
+		{"mock", "magic_svc"},
+		{"mock", "svc_a-"},
+		{"not_a_platform", "svc_a"},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("%s_%s", td.platform, td.cloudSvc)
+
+		t.Run(testName, func(t *testing.T) {
+			_, err := registry.NewPlugin(td.platform, td.cloudSvc, registry.PluginConfig{})
+			if err == nil {
+				t.Errorf("Error was expected, but not seen, when generating plugin for unknown service; using %s for unknown cloud service name", td.cloudSvc)
+			}
+		})
+	}
+}
+
+func TestRegister_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic when registering duplicate plugin, but didn't")
+		}
+	}()
+
+	registry.Register("mock", "svc_a", func(cfg registry.PluginConfig) registry.SearchPlugin {
+		return mockPlugin{}
+	})
+}
+
+type mockBulkPlugin struct {
+	mockPlugin
+}
+
+func (mbp mockBulkPlugin) SearchResourcesBulk(tgtIPs []string) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+	for _, tgtIP := range tgtIPs {
+		if tgtIP != "" {
+			matchingResources[tgtIP] = []generalResource.Resource{{RID: tgtIP + "-a"}, {RID: tgtIP + "-b"}}
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func TestSearchAllResources(t *testing.T) {
+	var tests = []struct {
+		testName     string
+		searchPlugin registry.SearchPlugin
+		tgtIP        string
+		expectedRIDs []string
+	}{
+		{"singleMatchPlugin", mockPlugin{}, "1.1.1.1", []string{"1.1.1.1"}},
+		{"singleMatchPluginNotFound", mockPlugin{}, "", nil},
+		{"bulkPlugin", mockBulkPlugin{}, "1.1.1.1", []string{"1.1.1.1-a", "1.1.1.1-b"}},
+		{"bulkPluginNotFound", mockBulkPlugin{}, "", nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			res, err := registry.SearchAllResources(td.searchPlugin, td.tgtIP)
+			if err != nil {
+				t.Fatalf("unexpected error received when searching all resources: %s", err)
+			}
+
+			var rids []string
+			for _, matchingResource := range res {
+				rids = append(rids, matchingResource.RID)
+			}
+
+			if !slices.Equal(rids, td.expectedRIDs) {
+				t.Errorf("Searching all resources failed; expected %v, received %v", td.expectedRIDs, rids)
+			}
+		})
+	}
+}
+
+func TestSearchResourcesBulk(t *testing.T) {
+	tgtIPs := []string{"1.1.1.1", "", "2.2.2.2"}
+
+	for _, searchPlugin := range []registry.SearchPlugin{mockPlugin{}, mockBulkPlugin{}} {
+		testName := fmt.Sprintf("%T", searchPlugin)
+
+		t.Run(testName, func(t *testing.T) {
+			res, err := registry.SearchResourcesBulk(searchPlugin, tgtIPs)
+			if err != nil {
+				t.Fatalf("unexpected error received when bulk searching resources: %s", err)
+			}
+
+			if len(res) != 2 || len(res["1.1.1.1"]) == 0 || len(res["2.2.2.2"]) == 0 {
+				t.Errorf("Bulk resource search failed; expected matches for 2 IPs, received %v", res)
+			}
+		})
+	}
+}
+
+func TestFirstMatch(t *testing.T) {
+	res, err := registry.FirstMatch(mockBulkPlugin{}, "1.1.1.1")
+	if err != nil {
+		t.Fatalf("unexpected error received when fetching first match: %s", err)
+	}
+
+	if res.RID != "1.1.1.1-a" {
+		t.Errorf("Fetching first match from bulk search failed; expected 1.1.1.1-a, received %s", res.RID)
+	}
+}
@@ -1,6 +1,91 @@
 package resource
 
+import (
+	"slices"
+
+	"github.com/magneticstain/ip-2-cloudresource/utils"
+)
+
 type Resource struct {
-	Id, RID, AccountID, Name, Status, CloudSvc                   string
+	Id, RID, AccountID, Name, Status, CloudSvc, Region           string
 	AccountAliases, NetworkMap, PublicIPv4Addrs, PublicIPv6Addrs []string
+	// only populated by private IP searches
+	PrivateIPv4Addrs, PrivateIPv6Addrs []string `json:",omitempty"`
+	// attributes hold service-specific details that don't fit any of the common fields, e.g. the association ID of an elastic IP
+	Tags, Attributes map[string]string `json:",omitempty"`
+	// DNS records that point at the resource, e.g. Route 53 A records containing its IP or aliases to its DNS name
+	DNSRecords []DNSRecord `json:",omitempty"`
+	// set when the IP could be classified, but not attributed to a specific resource
+	Classification *Classification `json:",omitempty"`
+}
+
+// DNSRecord describes a DNS record that resolves to a resource, along with the hosted zone it belongs to
+type DNSRecord struct {
+	FQDN, Type, ZoneID string
+	Alias, PrivateZone bool
+}
+
+// Classification describes an IP that belongs to a known cloud provider range that can't map to a customer resource, e.g. AWS-managed shared endpoints
+type Classification struct {
+	Type, Description, Platform, CloudSvc, Region, NetworkBorderGroup string
+}
+
+// AddPublicIPAddr records a public IP address associated with the resource under the appropriate IP version
+func (resource *Resource) AddPublicIPAddr(ipAddr string) {
+	ipVer, err := utils.DetermineIpAddrVersion(ipAddr)
+	if err != nil {
+		return
+	}
+
+	switch ipVer {
+	case 4:
+		if !slices.Contains(resource.PublicIPv4Addrs, ipAddr) {
+			resource.PublicIPv4Addrs = append(resource.PublicIPv4Addrs, ipAddr)
+		}
+	case 6:
+		if !slices.Contains(resource.PublicIPv6Addrs, ipAddr) {
+			resource.PublicIPv6Addrs = append(resource.PublicIPv6Addrs, ipAddr)
+		}
+	}
+}
+
+// AddPrivateIPAddr records a private IP address associated with the resource under the appropriate IP version
+func (resource *Resource) AddPrivateIPAddr(ipAddr string) {
+	ipVer, err := utils.DetermineIpAddrVersion(ipAddr)
+	if err != nil {
+		return
+	}
+
+	switch ipVer {
+	case 4:
+		if !slices.Contains(resource.PrivateIPv4Addrs, ipAddr) {
+			resource.PrivateIPv4Addrs = append(resource.PrivateIPv4Addrs, ipAddr)
+		}
+	case 6:
+		if !slices.Contains(resource.PrivateIPv6Addrs, ipAddr) {
+			resource.PrivateIPv6Addrs = append(resource.PrivateIPv6Addrs, ipAddr)
+		}
+	}
+}
+
+// AddIPAddr records an IP address associated with the resource as either private or public, depending on the type of search that matched it
+func (resource *Resource) AddIPAddr(ipAddr string, private bool) {
+	if private {
+		resource.AddPrivateIPAddr(ipAddr)
+	} else {
+		resource.AddPublicIPAddr(ipAddr)
+	}
+}
+
+// AddAttribute records a service-specific detail about the resource, skipping empty values
+func (resource *Resource) AddAttribute(key, value string) {
+	if value == "" {
+		return
+	}
+
+	if resource.Attributes == nil {
+		resource.Attributes = map[string]string{}
+	}
+
+	resource.Attributes[key] = value
 }
@@ -0,0 +1,50 @@
+package search
+
+import (
+	"maps"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+// IsSearchedSvc determines if resources of the service should be returned; resources without a plugin of their own (e.g. VPC endpoints) are covered by the eni service
+func (search Search) IsSearchedSvc(cloudSvc string) bool {
+	if slices.Contains(search.CloudSvcs, cloudSvc) {
+		return true
+	}
+
+	return slices.Contains(search.CloudSvcs, "eni") && !registry.IsSupportedSvc(search.Platform, cloudSvc)
+}
+
+// searchConfigAggregator looks the IPs up across every account and region recorded by the AWS Config aggregator in a single query, as an alternative to searching each account individually
+func (search Search) searchConfigAggregator(ipAddrs []string, doNetMapping bool) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	log.Info("searching AWS Config aggregator ", search.ConfigAggregator, " for ", len(ipAddrs), " IP(s)")
+
+	aggregatorResources, err := search.AWSCtrlr.SearchConfigAggregator(ipAddrs, doNetMapping)
+	if err != nil {
+		return matchingResources, err
+	}
+
+	for ipAddr, ipResources := range aggregatorResources {
+		for _, matchingResource := range ipResources {
+			if search.IsSearchedSvc(matchingResource.CloudSvc) {
+				matchingResources[ipAddr] = append(matchingResources[ipAddr], matchingResource)
+			}
+		}
+	}
+
+	if search.DNSRecordLookup && len(matchingResources) > 0 {
+		search.addDNSRecords(slices.Collect(maps.Values(matchingResources))...)
+	}
+
+	for _, ipResources := range matchingResources {
+		SortResources(ipResources)
+	}
+
+	return matchingResources, nil
+}
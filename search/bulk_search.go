@@ -0,0 +1,293 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/rollbar/rollbar-go"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+// BulkResult holds the outcome of a bulk search for a single IP
+type BulkResult struct {
+	IpAddr    string
+	Resources []generalResource.Resource
+}
+
+type acctBulkResult struct {
+	ipAddr    string
+	resources []generalResource.Resource
+}
+
+func (search *Search) RunBulkIPFuzzing(ipAddrs []string, doAdvIPFuzzing bool) ([]string, []BulkResult, error) {
+	// fuzz each IP to narrow down the services to search; since inventories are shared across every IP, the union of fuzzed services is searched
+	var classifiedResults []BulkResult
+	var searchableIPAddrs []string
+	var fuzzedSvcSets [][]string
+
+	for _, ipAddr := range ipAddrs {
+		search.IpAddr = ipAddr
+
+		svcSet, fuzzResult, err := search.RunIPFuzzing(doAdvIPFuzzing)
+		if err != nil {
+			return searchableIPAddrs, classifiedResults, err
+		}
+
+		classification := ClassifyFuzzedIP(fuzzResult)
+		if classification != nil {
+			classifiedResults = append(classifiedResults, BulkResult{
+				IpAddr:    ipAddr,
+				Resources: []generalResource.Resource{{Classification: classification}},
+			})
+
+			continue
+		}
+
+		searchableIPAddrs = append(searchableIPAddrs, ipAddr)
+		fuzzedSvcSets = append(fuzzedSvcSets, svcSet)
+	}
+	search.IpAddr = ""
+
+	if fuzzedSvcSet := MergeFuzzedSvcs(search.Platform, fuzzedSvcSets); len(fuzzedSvcSet) > 0 {
+		search.CloudSvcs = fuzzedSvcSet
+	}
+
+	return searchableIPAddrs, classifiedResults, nil
+}
+
+// MergeFuzzedSvcs combines the services fuzzed for each IP into a single prioritized set; if the service couldn't be determined for any of the IPs, no services are returned since every requested service will need to be searched anyways
+func MergeFuzzedSvcs(platform string, fuzzedSvcSets [][]string) []string {
+	var fuzzedSvcSet []string
+
+	for _, svcSet := range fuzzedSvcSets {
+		if len(svcSet) == 0 {
+			return nil
+		}
+
+		for _, svc := range svcSet {
+			if !slices.Contains(fuzzedSvcSet, svc) {
+				fuzzedSvcSet = append(fuzzedSvcSet, svc)
+			}
+		}
+	}
+
+	return registry.PrioritizeSvcs(platform, fuzzedSvcSet)
+}
+
+func (search Search) searchCloudSvcBulk(ipAddrs []string, svc string, doNetMapping bool) (map[string][]generalResource.Resource, error) {
+	switch search.Platform {
+	case "aws":
+		return search.AWSCtrlr.SearchAWSSvcBulk(ipAddrs, svc, doNetMapping)
+	case "azure":
+		return search.AzureCtrlr.SearchAzureSvcBulk(search.TenantID, ipAddrs, svc)
+	case "gcp":
+		return search.GCPCtrlr.SearchGCPSvcBulk(search.TenantID, ipAddrs, svc)
+	default:
+		return nil, fmt.Errorf("%s is not a supported platform for searching", search.Platform)
+	}
+}
+
+func (search Search) doAccountLevelBulkSearch(acctID string, ipAddrs []string, doNetMapping bool) (map[string][]generalResource.Resource, error) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	acctAliases, err := search.getAcctAliases(acctID)
+	if err != nil {
+		return matchingResources, err
+	}
+
+	if search.Platform == "aws" {
+		// region hints differ per IP, so every region is searched in a single pass
+		err = search.AWSCtrlr.LoadRegions()
+		if err != nil {
+			log.Warn("unable to enumerate enabled AWS regions, only the default region will be searched: ", err)
+		}
+	}
+
+	pendingIPAddrs := ipAddrs
+	for _, svc := range search.CloudSvcs {
+		svcResources, err := search.searchCloudSvcBulk(pendingIPAddrs, svc, doNetMapping)
+		if err != nil {
+			return matchingResources, err
+		}
+
+		for ipAddr, ipResources := range svcResources {
+			for _, matchingResource := range ipResources {
+				matchingResource.AccountID = acctID
+				matchingResource.AccountAliases = acctAliases
+
+				matchingResources[ipAddr] = append(matchingResources[ipAddr], matchingResource)
+			}
+		}
+
+		if !search.AllMatches {
+			// IPs that were already found don't need to be searched for in the remaining services
+			pendingIPAddrs = slices.DeleteFunc(slices.Clone(pendingIPAddrs), func(ipAddr string) bool {
+				return len(matchingResources[ipAddr]) > 0
+			})
+
+			if len(pendingIPAddrs) == 0 {
+				break
+			}
+		}
+	}
+
+	if search.DNSRecordLookup && len(matchingResources) > 0 {
+		search.addDNSRecords(slices.Collect(maps.Values(matchingResources))...)
+	}
+
+	return matchingResources, nil
+}
+
+func (search Search) runBulkSearchWorker(acctResultBuffer chan<- acctBulkResult, acctID string, ipAddrs []string, orgSearchRoleName string, doNetMapping bool, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	err := search.assumeAcctRole(acctID, orgSearchRoleName)
+	if err != nil {
+		log.Error("error when assuming role for account search worker: ", err)
+		return
+	}
+
+	resultResources, err := search.doAccountLevelBulkSearch(acctID, ipAddrs, doNetMapping)
+	if err != nil {
+		log.Error("error when running bulk search within account search worker: ", err)
+		return
+	}
+
+	for ipAddr, ipResources := range resultResources {
+		acctResultBuffer <- acctBulkResult{ipAddr: ipAddr, resources: ipResources}
+	}
+}
+
+func (search *Search) initBulkSearchWorkers(acctsToSearch []string, ipAddrs []string, orgSearchRoleName string, doNetMapping bool, resultHandler func(result BulkResult)) {
+	log.Info("beginning bulk resource gathering for ", len(ipAddrs), " IP(s)")
+
+	acctResultBuffer := make(chan acctBulkResult)
+	var wg sync.WaitGroup
+
+	for _, acctID := range acctsToSearch {
+		wg.Add(1)
+		go rollbar.WrapAndWait(
+			search.runBulkSearchWorker,
+			acctResultBuffer,
+			acctID,
+			ipAddrs,
+			orgSearchRoleName,
+			doNetMapping,
+			&wg,
+		)
+	}
+
+	go func() {
+		wg.Wait()
+		close(acctResultBuffer)
+	}()
+
+	matchingResources := map[string][]generalResource.Resource{}
+	for acctResult := range acctResultBuffer {
+		if search.AllMatches {
+			matchingResources[acctResult.ipAddr] = append(matchingResources[acctResult.ipAddr], acctResult.resources...)
+		} else if _, found := matchingResources[acctResult.ipAddr]; !found {
+			// stream the result as soon as the IP is found, since there's nothing else to wait on
+			matchingResources[acctResult.ipAddr] = acctResult.resources[:1]
+			resultHandler(BulkResult{IpAddr: acctResult.ipAddr, Resources: matchingResources[acctResult.ipAddr]})
+		}
+	}
+
+	for _, ipAddr := range ipAddrs {
+		ipResources, found := matchingResources[ipAddr]
+		if search.AllMatches {
+			SortResources(ipResources)
+			resultHandler(BulkResult{IpAddr: ipAddr, Resources: ipResources})
+		} else if !found {
+			resultHandler(BulkResult{IpAddr: ipAddr})
+		}
+	}
+}
+
+// StartBulkSearch searches for many IPs at once, fetching each service's inventory only once per account and region; one result is passed to the result handler per IP as soon as it's available
+func (search *Search) StartBulkSearch(ipAddrs []string, cloudSvc string, doIPFuzzing bool, doAdvIPFuzzing bool, doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchRoleName string, orgSearchOrgUnitID string, doNetMapping bool, resultHandler func(result BulkResult)) error {
+	if len(ipAddrs) == 0 {
+		return errors.New("no IPs provided for bulk search")
+	}
+
+	_, err := search.connectToPlatform()
+	if err != nil {
+		return fmt.Errorf("error when connecting to %s: %w", search.Platform, err)
+	}
+
+	search.CloudSvcs = search.ReconcileCloudSvcParam(cloudSvc)
+	err = search.ValidateCloudSvcs()
+	if err != nil {
+		return err
+	}
+
+	searchableIPAddrs := ipAddrs
+	if search.Platform == "aws" && search.IpamID != "" {
+		var ipamResources map[string][]generalResource.Resource
+
+		ipamResources, searchableIPAddrs = search.resolveViaIPAM(ipAddrs, doOrgSearch, orgSearchRoleName, doNetMapping)
+		for _, ipAddr := range ipAddrs {
+			if ipResources, found := ipamResources[ipAddr]; found {
+				if !search.AllMatches {
+					ipResources = ipResources[:1]
+				}
+
+				resultHandler(BulkResult{IpAddr: ipAddr, Resources: ipResources})
+			}
+		}
+
+		if len(searchableIPAddrs) == 0 {
+			return nil
+		}
+	}
+
+	if doIPFuzzing || doAdvIPFuzzing {
+		var classifiedResults []BulkResult
+
+		searchableIPAddrs, classifiedResults, err = search.RunBulkIPFuzzing(searchableIPAddrs, doAdvIPFuzzing)
+		if err != nil {
+			return err
+		}
+
+		for _, classifiedResult := range classifiedResults {
+			resultHandler(classifiedResult)
+		}
+
+		if len(searchableIPAddrs) == 0 {
+			return nil
+		}
+	}
+
+	if search.Platform == "aws" && search.ConfigAggregator != "" {
+		aggregatorResources, err := search.searchConfigAggregator(searchableIPAddrs, doNetMapping)
+		if err != nil {
+			return err
+		}
+
+		for _, ipAddr := range searchableIPAddrs {
+			ipResources := aggregatorResources[ipAddr]
+			if !search.AllMatches && len(ipResources) > 0 {
+				ipResources = ipResources[:1]
+			}
+
+			resultHandler(BulkResult{IpAddr: ipAddr, Resources: ipResources})
+		}
+
+		return nil
+	}
+
+	acctsToSearch, err := search.getAcctsToSearch(doOrgSearch, orgSearchXaccountRoleARN, orgSearchOrgUnitID)
+	if err != nil {
+		return err
+	}
+
+	search.initBulkSearchWorkers(acctsToSearch, searchableIPAddrs, orgSearchRoleName, doNetMapping, resultHandler)
+
+	return nil
+}
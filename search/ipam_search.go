@@ -0,0 +1,113 @@
+package search
+
+import (
+	"maps"
+	"slices"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/magneticstain/ip-2-cloudresource/registry"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+)
+
+// GetIPAMEnrichmentSvcs determines which services to search for more details about a resource found via IPAM; no services are returned if there's no plugin that can add anything
+func (search Search) GetIPAMEnrichmentSvcs(ipamResource generalResource.Resource) []string {
+	// IPAM only knew which network the IP belongs to, so any of the services could be using it
+	if ipamResource.CloudSvc == "vpc" {
+		return search.CloudSvcs
+	}
+
+	if registry.IsSupportedSvc(search.Platform, ipamResource.CloudSvc) && slices.Contains(search.CloudSvcs, ipamResource.CloudSvc) {
+		return []string{ipamResource.CloudSvc}
+	}
+
+	return nil
+}
+
+// enrichIPAMResource searches the account, region, and service that IPAM attributed the IP to, which is much narrower than a regular search
+func (search Search) enrichIPAMResource(ipAddr string, ipamResource generalResource.Resource, doOrgSearch bool, orgSearchRoleName string, doNetMapping bool) []generalResource.Resource {
+	enrichmentSvcs := search.GetIPAMEnrichmentSvcs(ipamResource)
+	if len(enrichmentSvcs) == 0 {
+		return nil
+	}
+
+	acctID := "current"
+	if doOrgSearch && ipamResource.AccountID != "" {
+		acctID = ipamResource.AccountID
+	}
+
+	search.IpAddr = ipAddr
+	search.CloudSvcs = enrichmentSvcs
+	search.RegionHint = ""
+	if ipamResource.Region != "" {
+		search.AWSCtrlr.Regions = []string{ipamResource.Region}
+	}
+	if search.PrivateSearch && search.AWSCtrlr.VpcID == "" {
+		search.AWSCtrlr.VpcID = ipamResource.Attributes["VpcId"]
+	}
+
+	err := search.assumeAcctRole(acctID, orgSearchRoleName)
+	if err != nil {
+		log.Warn("unable to assume role for enriching IPAM result, IPAM details will be used instead: ", err)
+		return nil
+	}
+
+	enrichedResources, err := search.doAccountLevelSearch(acctID, doNetMapping)
+	if err != nil {
+		log.Warn("unable to enrich IPAM result, IPAM details will be used instead: ", err)
+		return nil
+	}
+
+	return enrichedResources
+}
+
+// resolveViaIPAM looks the IPs up in IPAM org-wide, then uses the service plugins to fill in the details IPAM doesn't track; IPs that IPAM has no answer for are returned so they can be searched for normally
+func (search Search) resolveViaIPAM(ipAddrs []string, doOrgSearch bool, orgSearchRoleName string, doNetMapping bool) (map[string][]generalResource.Resource, []string) {
+	matchingResources := map[string][]generalResource.Resource{}
+
+	log.Info("resolving IP(s) via IPAM ", search.IpamID)
+
+	ipamResources, err := search.AWSCtrlr.SearchIPAM(ipAddrs, doNetMapping)
+	if err != nil {
+		// a misconfigured or unreachable IPAM shouldn't prevent the IPs from being found
+		log.Warn("unable to resolve IP(s) via IPAM, falling back to searching each service: ", err)
+		return matchingResources, ipAddrs
+	}
+
+	var unresolvedIPAddrs []string
+	unenrichedResources := map[string][]generalResource.Resource{}
+
+	for _, ipAddr := range ipAddrs {
+		if len(ipamResources[ipAddr]) == 0 {
+			unresolvedIPAddrs = append(unresolvedIPAddrs, ipAddr)
+			continue
+		}
+
+		for _, ipamResource := range ipamResources[ipAddr] {
+			enrichedResources := search.enrichIPAMResource(ipAddr, ipamResource, doOrgSearch, orgSearchRoleName, doNetMapping)
+			if len(enrichedResources) > 0 {
+				matchingResources[ipAddr] = append(matchingResources[ipAddr], enrichedResources...)
+			} else {
+				unenrichedResources[ipAddr] = append(unenrichedResources[ipAddr], ipamResource)
+			}
+		}
+	}
+
+	// enriched resources already had their DNS records looked up as part of the account search
+	if search.DNSRecordLookup && len(unenrichedResources) > 0 {
+		search.addDNSRecords(slices.Collect(maps.Values(unenrichedResources))...)
+	}
+
+	for ipAddr, ipResources := range unenrichedResources {
+		matchingResources[ipAddr] = append(matchingResources[ipAddr], ipResources...)
+	}
+	for _, ipResources := range matchingResources {
+		SortResources(ipResources)
+	}
+
+	if len(unresolvedIPAddrs) > 0 {
+		log.Info(len(unresolvedIPAddrs), " IP(s) not found in IPAM, falling back to searching each service")
+	}
+
+	return matchingResources, unresolvedIPAddrs
+}
@@ -1,8 +1,10 @@
 package search
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
@@ -13,19 +15,32 @@ import (
 	awscontroller "github.com/magneticstain/ip-2-cloudresource/aws"
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
 	iamp "github.com/magneticstain/ip-2-cloudresource/aws/plugin/iam"
+	route53p "github.com/magneticstain/ip-2-cloudresource/aws/plugin/route53"
 	ipfuzzing "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing"
 	azurecontroller "github.com/magneticstain/ip-2-cloudresource/azure"
 	gcpcontroller "github.com/magneticstain/ip-2-cloudresource/gcp"
+	"github.com/magneticstain/ip-2-cloudresource/registry"
 	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
+	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
 type Search struct {
+	AllMatches                 bool
 	AWSCtrlr                   awscontroller.AWSController
 	AzureCtrlr                 azurecontroller.AzureController
 	CloudSvcs                  []string
+	ConfigAggregator           string
+	DNSRecordLookup            bool
 	GCPCtrlr                   gcpcontroller.GCPController
 	MatchedResource            generalResource.Resource
+	MatchedResources           []generalResource.Resource
 	IpAddr, Platform, TenantID string
+	IPRangeSrc                 ipfuzzing.IPRangeSource
+	IpamID                     string
+	PrivateSearch              bool
+	Regions                    []string
+	RegionHint                 string
+	VpcID                      string
 }
 
 func (search *Search) connectToPlatform() (bool, error) {
@@ -38,6 +53,11 @@ func (search *Search) connectToPlatform() (bool, error) {
 		if err != nil {
 			return false, err
 		}
+		ac.Regions = search.Regions
+		ac.PrivateSearch = search.PrivateSearch
+		ac.VpcID = search.VpcID
+		ac.IpamID = search.IpamID
+		ac.ConfigAggregator = search.ConfigAggregator
 
 		search.AWSCtrlr = ac
 	case "azure":
@@ -55,15 +75,11 @@ func (search *Search) connectToPlatform() (bool, error) {
 func (search Search) ReconcileCloudSvcParam(cloudSvc string) []string {
 	var cloudSvcs []string
 
-	if cloudSvc == "all" {
-		switch search.Platform {
-		case "aws":
-			cloudSvcs = awscontroller.GetSupportedSvcs()
-		case "azure":
-			cloudSvcs = azurecontroller.GetSupportedSvcs()
-		case "gcp":
-			cloudSvcs = gcpcontroller.GetSupportedSvcs()
-		}
+	if cloudSvc == "all" && search.PrivateSearch {
+		// only some services can match private IPs
+		cloudSvcs = registry.GetPrivateSearchSvcs(search.Platform)
+	} else if cloudSvc == "all" {
+		cloudSvcs = registry.GetSupportedSvcs(search.Platform)
 	} else if strings.Contains(cloudSvc, ",") {
 		// csv provided, split the values into a slice
 		cloudSvcs = strings.Split(cloudSvc, ",")
@@ -72,106 +88,318 @@ func (search Search) ReconcileCloudSvcParam(cloudSvc string) []string {
 		cloudSvcs = []string{cloudSvc}
 	}
 
-	return cloudSvcs
+	return registry.PrioritizeSvcs(search.Platform, cloudSvcs)
 }
 
-func (search Search) RunIPFuzzing(doAdvIPFuzzing bool) ([]string, error) {
+func (search Search) ValidateCloudSvcs() error {
+	for _, svc := range search.CloudSvcs {
+		if !registry.IsSupportedSvc(search.Platform, svc) {
+			return fmt.Errorf("'%s' is not a supported %s service; supported services are: %s", svc, strings.ToUpper(search.Platform), utils.FormatStrSliceAsCSV(registry.GetSupportedSvcs(search.Platform)))
+		}
+
+		if search.PrivateSearch && !registry.SupportsPrivateSearch(search.Platform, svc) {
+			return fmt.Errorf("'%s' does not support private IP searches; supported services are: %s", svc, utils.FormatStrSliceAsCSV(registry.GetPrivateSearchSvcs(search.Platform)))
+		}
+	}
+
+	return nil
+}
+
+const AWSManagedEndpointClassification string = "aws_managed_shared_endpoint"
+
+// services listed in AWS's IP ranges that are searched by a plugin with a different name
+var fuzzedSvcPlugins = map[string]string{
+	"api_gateway": "apigateway",
+}
+
+func (search Search) RunIPFuzzing(doAdvIPFuzzing bool) ([]string, ipfuzzing.FuzzResult, error) {
 	var svcSet []string
-	var fuzzedSvc string
+	var fuzzResult ipfuzzing.FuzzResult
 	var err error
 
-	fuzzedSvc, err = ipfuzzing.FuzzIP(search.IpAddr, doAdvIPFuzzing)
+	fuzzResult, err = ipfuzzing.FuzzIP(search.IpAddr, doAdvIPFuzzing, search.IPRangeSrc)
 	if err != nil {
-		return svcSet, err
+		return svcSet, fuzzResult, err
+	}
+
+	if regionHint := GetRegionHint(fuzzResult); regionHint != "" {
+		log.Info("IP fuzzing determined the associated region is: ", regionHint)
 	}
 
 	// normalize service name to lowercase
-	fuzzedSvc = strings.ToLower(fuzzedSvc)
+	fuzzedSvc := strings.ToLower(fuzzResult.Service)
 
 	if fuzzedSvc == "" || fuzzedSvc == "unknown" {
 		log.Info("could not determine service via IP fuzzing")
-		return svcSet, err
+		return svcSet, fuzzResult, err
 	}
 
 	log.Info("IP fuzzing determined the associated cloud service is: ", fuzzedSvc)
-	svcSet = append(svcSet, fuzzedSvc)
+
+	return GetFuzzedSvcs(fuzzedSvc), fuzzResult, err
+}
+
+// GetFuzzedSvcs determines which services to search for the service that IP fuzzing attributed an IP to; no services are returned for AWS-managed services, since there's no customer resource to search for
+func GetFuzzedSvcs(fuzzedSvc string) []string {
+	fuzzedSvc = strings.ToLower(fuzzedSvc)
+
+	if fuzzedSvc == "" || fuzzedSvc == "unknown" || ipfuzzing.IsAWSManagedSvc(fuzzedSvc) {
+		return nil
+	}
+
+	if pluginSvc, ok := fuzzedSvcPlugins[fuzzedSvc]; ok {
+		fuzzedSvc = pluginSvc
+	}
+
+	svcSet := []string{fuzzedSvc}
+
+	// edge-optimized APIs and custom domains are served by AWS-managed CloudFront distributions that aren't visible in the CloudFront API
+	// the same goes for Lightsail distributions
+	if fuzzedSvc == "cloudfront" {
+		svcSet = append(svcSet, "apigateway", "lightsail")
+	}
 
 	// all ELBs act within EC2 infrastructure, so we will need to add the elb services as well if that's the case
+	// elastic IPs, public database endpoints, Lightsail resources, and other resources backed by ENIs (e.g. NAT gateways or Fargate tasks) also use EC2 IPs
 	if fuzzedSvc == "ec2" {
-		svcSet = append(svcSet, "elbv1", "elbv2")
+		svcSet = append(svcSet, "ecs", "eip", "elbv1", "elbv2", "lightsail", "natgw", "rds", "redshift", "eni")
 	}
 
-	return svcSet, err
+	return svcSet
 }
 
-func (search Search) doAccountLevelSearch(acctID string, doNetMapping bool) (generalResource.Resource, error) {
-	var acctAliases []string
+func GetRegionHint(fuzzResult ipfuzzing.FuzzResult) string {
+	// IP ranges for global services (e.g. CloudFront) are marked as GLOBAL, which won't help narrow down the regions to search
+	if fuzzResult.Region == "GLOBAL" {
+		return ""
+	}
+
+	return fuzzResult.Region
+}
+
+func ClassifyFuzzedIP(fuzzResult ipfuzzing.FuzzResult) *generalResource.Classification {
+	if !ipfuzzing.IsAWSManagedSvc(fuzzResult.Service) {
+		return nil
+	}
+
+	location := "global"
+	if regionHint := GetRegionHint(fuzzResult); regionHint != "" {
+		location = "in " + regionHint
+	}
+
+	return &generalResource.Classification{
+		Type:               AWSManagedEndpointClassification,
+		Description:        fmt.Sprintf("AWS-managed shared endpoint (%s %s), cannot map to customer resource", strings.ToUpper(fuzzResult.Service), location),
+		Platform:           "aws",
+		CloudSvc:           strings.ToLower(fuzzResult.Service),
+		Region:             fuzzResult.Region,
+		NetworkBorderGroup: fuzzResult.NetworkBorderGroup,
+	}
+}
+
+func (search Search) searchCloudSvc(svc string, doNetMapping bool) ([]generalResource.Resource, error) {
 	var matchingResource generalResource.Resource
 	var err error
 
-	if acctID != "current" && search.Platform == "aws" {
-		// resolve account's aliases
-		iamp := iamp.IAMPlugin{AwsConn: search.AWSCtrlr.PrincipalAWSConn}
-		acctAliases, err = iamp.GetResources()
-		if err != nil {
-			return matchingResource, err
+	switch search.Platform {
+	case "aws":
+		if search.AllMatches {
+			return search.AWSCtrlr.SearchAWSSvcAll(search.IpAddr, svc, doNetMapping)
 		}
 
-		log.Info("starting resource search in AWS account: ", acctID, " ", acctAliases)
-	} else {
-		log.Info("starting resource search in current account")
+		matchingResource, err = search.AWSCtrlr.SearchAWSSvc(search.IpAddr, svc, doNetMapping)
+	case "azure":
+		if search.AllMatches {
+			svcResources, err := search.AzureCtrlr.SearchAzureSvcBulk(search.TenantID, []string{search.IpAddr}, svc)
+			return svcResources[search.IpAddr], err
+		}
+
+		matchingResource, err = search.AzureCtrlr.SearchAzureSvc(search.TenantID, search.IpAddr, svc)
+	case "gcp":
+		if search.AllMatches {
+			svcResources, err := search.GCPCtrlr.SearchGCPSvcBulk(search.TenantID, []string{search.IpAddr}, svc)
+			return svcResources[search.IpAddr], err
+		}
+
+		matchingResource, err = search.GCPCtrlr.SearchGCPSvc(search.TenantID, search.IpAddr, svc)
+	default:
+		errorMsg := fmt.Sprintf("%s is not a supported platform for searching", search.Platform)
+		return nil, errors.New(errorMsg)
 	}
 
+	if err != nil || matchingResource.RID == "" {
+		return nil, err
+	}
+
+	return []generalResource.Resource{matchingResource}, nil
+}
+
+func (search Search) searchCloudSvcs(doNetMapping bool) ([]generalResource.Resource, error) {
+	var matchingResources []generalResource.Resource
+
 	for _, svc := range search.CloudSvcs {
-		switch search.Platform {
-		case "aws":
-			matchingResource, err = search.AWSCtrlr.SearchAWSSvc(search.IpAddr, svc, doNetMapping)
-		case "azure":
-			matchingResource, err = search.AzureCtrlr.SearchAzureSvc(search.TenantID, search.IpAddr, svc, &matchingResource)
-		case "gcp":
-			matchingResource, err = search.GCPCtrlr.SearchGCPSvc(search.TenantID, search.IpAddr, svc, &matchingResource)
-		default:
-			errorMsg := fmt.Sprintf("%s is not a supported platform for searching", search.Platform)
-			return matchingResource, errors.New(errorMsg)
+		svcResources, err := search.searchCloudSvc(svc, doNetMapping)
+		if err != nil {
+			return matchingResources, err
 		}
 
-		if err != nil {
-			return matchingResource, err
-		} else if matchingResource.RID != "" {
+		matchingResources = append(matchingResources, svcResources...)
+		if len(matchingResources) > 0 && !search.AllMatches {
 			// resource was found
+			break
+		}
+	}
+
+	return matchingResources, nil
+}
+
+func (search Search) doAccountLevelSearch(acctID string, doNetMapping bool) ([]generalResource.Resource, error) {
+	var matchingResources []generalResource.Resource
+
+	acctAliases, err := search.getAcctAliases(acctID)
+	if err != nil {
+		return matchingResources, err
+	}
+
+	// only AWS searches are region-scoped, so every other platform is searched in a single pass
+	regionPasses := [][]string{nil}
+	if search.Platform == "aws" {
+		err = search.AWSCtrlr.LoadRegions()
+		if err != nil {
+			log.Warn("unable to enumerate enabled AWS regions, only the default region will be searched: ", err)
+		}
+
+		regionHint := search.RegionHint
+		if search.AllMatches {
+			// every region gets searched regardless, so there's no benefit to splitting the search into passes
+			regionHint = ""
+		}
+
+		regionPasses = awscontroller.PrioritizeRegions(search.AWSCtrlr.Regions, regionHint)
+	}
+
+	for i, regions := range regionPasses {
+		if search.Platform == "aws" {
+			if i > 0 {
+				log.Info("resource not found in region ", search.RegionHint, ", expanding search to remaining regions")
+			}
+
+			search.AWSCtrlr.Regions = regions
+		}
+
+		passResources, err := search.searchCloudSvcs(doNetMapping)
+		if err != nil {
+			return matchingResources, err
+		}
+
+		for _, matchingResource := range passResources {
 			matchingResource.AccountID = acctID
 			matchingResource.AccountAliases = acctAliases
 
+			matchingResources = append(matchingResources, matchingResource)
+		}
+
+		if len(matchingResources) > 0 {
+			// resource was found
 			break
 		}
 	}
 
-	return matchingResource, nil
+	if search.DNSRecordLookup && len(matchingResources) > 0 {
+		search.addDNSRecords(matchingResources)
+	}
+
+	return matchingResources, nil
 }
 
-func (search Search) runSearchWorker(matchingResourceBuffer chan<- generalResource.Resource, acctID string, orgSearchRoleName string, doNetMapping bool, wg *sync.WaitGroup) {
-	defer wg.Done()
+func (search Search) addDNSRecords(resourceSets ...[]generalResource.Resource) {
+	// Route 53 is only available for AWS
+	if search.Platform != "aws" {
+		return
+	}
+
+	// every hosted zone in the account is scanned once, regardless of how many resources need to be looked up
+	r53p := route53p.Route53Plugin{AwsConn: search.AWSCtrlr.PrincipalAWSConn}
+	recordIdx, err := r53p.GetRecordIndex()
+	if err != nil {
+		// missing Route 53 permissions shouldn't prevent the matched resources from being returned
+		log.Warn("unable to look up Route 53 records for matched resources: ", err)
+		return
+	}
 
+	for _, resources := range resourceSets {
+		for i := range resources {
+			resources[i].DNSRecords = recordIdx.Lookup(resources[i])
+		}
+	}
+}
+
+func (search *Search) assumeAcctRole(acctID string, orgSearchRoleName string) error {
 	// org support is only available for AWS at this time
 	if acctID != "current" && search.Platform == "aws" {
 		// replace connector with assumed role connector before running rest of logic
 		acctRoleArn := fmt.Sprintf("arn:aws:iam::%s:role/%s", acctID, orgSearchRoleName)
 		ac, err := awsconnector.NewAWSConnectorAssumeRole(acctRoleArn, aws.Config{})
 		if err != nil {
-			log.Error("error when assuming role for account search worker: ", err)
-			return
+			return err
 		}
 
 		search.AWSCtrlr.PrincipalAWSConn = ac
 	}
 
-	resultResource, err := search.doAccountLevelSearch(acctID, doNetMapping)
+	return nil
+}
+
+func (search Search) getAcctAliases(acctID string) ([]string, error) {
+	var acctAliases []string
+	var err error
+
+	if acctID != "current" && search.Platform == "aws" {
+		// resolve account's aliases
+		iamp := iamp.IAMPlugin{AwsConn: search.AWSCtrlr.PrincipalAWSConn}
+		acctAliases, err = iamp.GetResources()
+		if err != nil {
+			return acctAliases, err
+		}
+
+		log.Info("starting resource search in AWS account: ", acctID, " ", acctAliases)
+	} else {
+		log.Info("starting resource search in current account")
+	}
+
+	return acctAliases, nil
+}
+
+func (search Search) runSearchWorker(matchingResourceBuffer chan<- generalResource.Resource, acctID string, orgSearchRoleName string, doNetMapping bool, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	err := search.assumeAcctRole(acctID, orgSearchRoleName)
+	if err != nil {
+		log.Error("error when assuming role for account search worker: ", err)
+		return
+	}
+
+	resultResources, err := search.doAccountLevelSearch(acctID, doNetMapping)
 	if err != nil {
 		log.Error("error when running search within account search worker: ", err)
-	} else if resultResource.RID != "" {
-		matchingResourceBuffer <- resultResource
 		return
 	}
+
+	for _, resultResource := range resultResources {
+		matchingResourceBuffer <- resultResource
+	}
+}
+
+func SortResources(resources []generalResource.Resource) {
+	// results arrive in whatever order the workers finish in, so they're sorted to keep output stable between runs
+	slices.SortStableFunc(resources, func(a, b generalResource.Resource) int {
+		return cmp.Or(
+			cmp.Compare(a.AccountID, b.AccountID),
+			cmp.Compare(a.CloudSvc, b.CloudSvc),
+			cmp.Compare(a.Region, b.Region),
+			cmp.Compare(a.RID, b.RID),
+		)
+	})
 }
 
 func (search *Search) initSearchWorkers(acctsToSearch []string, orgSearchRoleName string, doNetMapping bool) bool {
@@ -197,12 +425,40 @@ func (search *Search) initSearchWorkers(acctsToSearch []string, orgSearchRoleNam
 		close(matchingResourceBuffer)
 	}()
 
-	resultResource, found := <-matchingResourceBuffer
-	if found {
-		search.MatchedResource = resultResource
+	// every result is drained, even when only the first match is wanted, so that no worker is left blocked on the buffer
+	var resultResources []generalResource.Resource
+	for resultResource := range matchingResourceBuffer {
+		resultResources = append(resultResources, resultResource)
+	}
+	SortResources(resultResources)
+
+	return search.setMatchedResources(resultResources)
+}
+
+// setMatchedResources records the resources found by the account search workers, IPAM, or an AWS Config aggregator
+func (search *Search) setMatchedResources(matchingResources []generalResource.Resource) bool {
+	if len(matchingResources) == 0 {
+		return false
+	}
+
+	if !search.AllMatches {
+		matchingResources = matchingResources[:1]
+	}
+
+	search.MatchedResources = matchingResources
+	search.MatchedResource = matchingResources[0]
+
+	return true
+}
+
+func (search Search) getAcctsToSearch(doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchOrgUnitID string) ([]string, error) {
+	if !doOrgSearch {
+		return []string{"current"}, nil
 	}
 
-	return found
+	log.Info("starting org account enumeration")
+
+	return search.AWSCtrlr.FetchOrgAcctIds(orgSearchOrgUnitID, orgSearchXaccountRoleARN)
 }
 
 func (search *Search) StartSearch(cloudSvc string, doIPFuzzing bool, doAdvIPFuzzing bool, doOrgSearch bool, orgSearchXaccountRoleARN string, orgSearchRoleName string, orgSearchOrgUnitID string, doNetMapping bool) (bool, error) {
@@ -216,24 +472,53 @@ func (search *Search) StartSearch(cloudSvc string, doIPFuzzing bool, doAdvIPFuzz
 
 	// TODO: move this to init function
 	search.CloudSvcs = search.ReconcileCloudSvcParam(cloudSvc)
+	err = search.ValidateCloudSvcs()
+	if err != nil {
+		return resourceFound, err
+	}
+
+	if search.Platform == "aws" && search.IpamID != "" {
+		// falls back to searching each service the same way bulk searches do, i.e. only if IPAM couldn't resolve the IP
+		ipamResources, unresolvedIPAddrs := search.resolveViaIPAM([]string{search.IpAddr}, doOrgSearch, orgSearchRoleName, doNetMapping)
+		if len(unresolvedIPAddrs) == 0 {
+			return search.setMatchedResources(ipamResources[search.IpAddr]), nil
+		}
+	}
 
 	if doIPFuzzing || doAdvIPFuzzing {
-		search.CloudSvcs, err = search.RunIPFuzzing(doAdvIPFuzzing)
+		fuzzedSvcSet, fuzzResult, err := search.RunIPFuzzing(doAdvIPFuzzing)
 		if err != nil {
 			return resourceFound, err
 		}
-	}
 
-	var acctsToSearch []string
-	if doOrgSearch {
-		log.Info("starting org account enumeration")
+		// fuzzing can only narrow the search; when it can't attribute the IP to a service (e.g. private IPs), the requested services are kept
+		if len(fuzzedSvcSet) > 0 {
+			search.CloudSvcs = fuzzedSvcSet
+		}
+		search.RegionHint = GetRegionHint(fuzzResult)
+
+		classification := ClassifyFuzzedIP(fuzzResult)
+		if classification != nil {
+			search.MatchedResource.Classification = classification
+			search.MatchedResources = []generalResource.Resource{search.MatchedResource}
+
+			return resourceFound, nil
+		}
+	}
 
-		acctsToSearch, err = search.AWSCtrlr.FetchOrgAcctIds(orgSearchOrgUnitID, orgSearchXaccountRoleARN)
+	if search.Platform == "aws" && search.ConfigAggregator != "" {
+		// the aggregator already covers every account it collects from, so there's no need to assume a role in each one
+		aggregatorResources, err := search.searchConfigAggregator([]string{search.IpAddr}, doNetMapping)
 		if err != nil {
 			return resourceFound, err
 		}
-	} else {
-		acctsToSearch = append(acctsToSearch, "current")
+
+		return search.setMatchedResources(aggregatorResources[search.IpAddr]), nil
+	}
+
+	acctsToSearch, err := search.getAcctsToSearch(doOrgSearch, orgSearchXaccountRoleARN, orgSearchOrgUnitID)
+	if err != nil {
+		return resourceFound, err
 	}
 
 	resourceFound = search.initSearchWorkers(acctsToSearch, orgSearchRoleName, doNetMapping)
@@ -3,13 +3,16 @@ package search_test
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 
 	awscontroller "github.com/magneticstain/ip-2-cloudresource/aws"
 	awsconnector "github.com/magneticstain/ip-2-cloudresource/aws/aws_connector"
+	ipfuzzing "github.com/magneticstain/ip-2-cloudresource/aws/svc/ip_fuzzing"
 	gcpcontroller "github.com/magneticstain/ip-2-cloudresource/gcp"
+	generalResource "github.com/magneticstain/ip-2-cloudresource/resource"
 	"github.com/magneticstain/ip-2-cloudresource/search"
 	"golang.org/x/exp/slices"
 )
@@ -49,10 +52,18 @@ func ipFactory() []TestIPAddr {
 
 func ipFuzzingCloudSvcsFactory() []string {
 	cloudSvcs := []string{
+		"apigateway",
 		"cloudfront",
 		"ec2",
+		"ecs",
+		"eip",
 		"elbv1",
 		"elbv2",
+		"eni",
+		"lightsail",
+		"natgw",
+		"rds",
+		"redshift",
 		"unknown",
 	}
 
@@ -65,10 +76,19 @@ func TestReconcileCloudSvcParam_AllSvcs(t *testing.T) {
 		expectedCloudSvcSet []string
 	}{
 		{"aws", "all", []string{
+			"apigateway",
 			"cloudfront",
 			"ec2",
+			"ecs",
+			"eip",
 			"elbv1",
 			"elbv2",
+			"eni",
+			"globalaccelerator",
+			"lightsail",
+			"natgw",
+			"rds",
+			"redshift",
 		}},
 		{"gcp", "all", []string{
 			"compute",
@@ -95,6 +115,29 @@ func TestReconcileCloudSvcParam_AllSvcs(t *testing.T) {
 	}
 }
 
+func TestReconcileCloudSvcParam_FallbackSvcsLast(t *testing.T) {
+	search := searchFactory("")
+	search.Platform = "aws"
+
+	res := search.ReconcileCloudSvcParam("all")
+	if res[len(res)-1] != "eni" {
+		t.Errorf("Cloud service reconciliation failed; expected fallback eni service to be searched last, received %v", res)
+	}
+}
+
+func TestReconcileCloudSvcParam_PrivateSearch(t *testing.T) {
+	search := searchFactory("")
+	search.Platform = "aws"
+	search.PrivateSearch = true
+
+	expectedCloudSvcSet := []string{"ec2", "elbv1", "elbv2", "rds", "eni"}
+
+	res := search.ReconcileCloudSvcParam("all")
+	if !slices.Equal(res, expectedCloudSvcSet) {
+		t.Errorf("Cloud service reconciliation for private search failed; expected %v, received %v", expectedCloudSvcSet, res)
+	}
+}
+
 func TestReconcileCloudSvcParam_InvalidSvcs(t *testing.T) {
 	var tests = []struct {
 		platform, cloudSvc  string
@@ -182,6 +225,145 @@ func TestReconcileCloudSvcParam_SingleSvc(t *testing.T) {
 	}
 }
 
+func TestValidateCloudSvcs(t *testing.T) {
+	var tests = []struct {
+		platform      string
+		cloudSvcs     []string
+		privateSearch bool
+		valid         bool
+	}{
+		{"aws", []string{"ec2", "elbv1", "elbv2"}, false, true},
+		{"gcp", []string{"compute", "cloud_sql"}, false, true},
+		{"aws", []string{"ec2", "not_a_svc"}, false, false},
+		{"gcp", []string{"cloudfront"}, false, false},
+		{"aws", []string{"ec2", "rds", "eni"}, true, true},
+		{"aws", []string{"ec2", "cloudfront"}, true, false},
+	}
+
+	for _, td := range tests {
+		testName := fmt.Sprintf("%s_%s_%t", td.platform, strings.Join(td.cloudSvcs, ","), td.privateSearch)
+
+		search := searchFactory("")
+
+		t.Run(testName, func(t *testing.T) {
+			search.Platform = td.platform
+			search.CloudSvcs = td.cloudSvcs
+			search.PrivateSearch = td.privateSearch
+
+			err := search.ValidateCloudSvcs()
+			if (err == nil) != td.valid {
+				t.Errorf("Cloud service validation failed; expected valid: %t, received error: %v", td.valid, err)
+			}
+		})
+	}
+}
+
+func TestClassifyFuzzedIP(t *testing.T) {
+	var tests = []struct {
+		fuzzResult          ipfuzzing.FuzzResult
+		expectedDescription string
+	}{
+		{ipfuzzing.FuzzResult{Service: "S3", Region: "us-east-1"}, "AWS-managed shared endpoint (S3 in us-east-1), cannot map to customer resource"},
+		{ipfuzzing.FuzzResult{Service: "ROUTE53_HEALTHCHECKS", Region: "GLOBAL"}, "AWS-managed shared endpoint (ROUTE53_HEALTHCHECKS global), cannot map to customer resource"},
+		{ipfuzzing.FuzzResult{Service: "dynamodb", Region: "eu-west-1"}, "AWS-managed shared endpoint (DYNAMODB in eu-west-1), cannot map to customer resource"},
+		{ipfuzzing.FuzzResult{Service: "EC2", Region: "us-east-1"}, ""},
+		{ipfuzzing.FuzzResult{Service: "API_GATEWAY", Region: "us-east-1"}, ""},
+		{ipfuzzing.FuzzResult{Service: "GLOBALACCELERATOR", Region: "GLOBAL"}, ""},
+		{ipfuzzing.FuzzResult{Service: "UNKNOWN"}, ""},
+	}
+
+	for _, td := range tests {
+		testName := td.fuzzResult.Service
+
+		t.Run(testName, func(t *testing.T) {
+			classification := search.ClassifyFuzzedIP(td.fuzzResult)
+
+			if td.expectedDescription == "" {
+				if classification != nil {
+					t.Errorf("IP classification failed; expected no classification for %s, received: %s", td.fuzzResult.Service, classification.Description)
+				}
+			} else if classification == nil {
+				t.Errorf("IP classification failed; expected classification for %s, received none", td.fuzzResult.Service)
+			} else if classification.Description != td.expectedDescription || classification.Type != search.AWSManagedEndpointClassification {
+				t.Errorf("IP classification failed; expected: %s, received: %s (%s)", td.expectedDescription, classification.Description, classification.Type)
+			}
+		})
+	}
+}
+
+func TestSortResources(t *testing.T) {
+	resources := []generalResource.Resource{
+		{AccountID: "222222222222", CloudSvc: "ec2", Region: "us-east-1", RID: "i-2"},
+		{AccountID: "111111111111", CloudSvc: "elbv2", Region: "us-east-1", RID: "lb-1"},
+		{AccountID: "111111111111", CloudSvc: "ec2", Region: "us-west-2", RID: "i-1"},
+		{AccountID: "111111111111", CloudSvc: "ec2", Region: "eu-west-1", RID: "i-3"},
+	}
+
+	search.SortResources(resources)
+
+	var rids []string
+	for _, resource := range resources {
+		rids = append(rids, resource.RID)
+	}
+
+	expectedRIDs := []string{"i-3", "i-1", "lb-1", "i-2"}
+	if !slices.Equal(rids, expectedRIDs) {
+		t.Errorf("Sorting matched resources failed; expected %v, received %v", expectedRIDs, rids)
+	}
+}
+
+func TestGetIPAMEnrichmentSvcs(t *testing.T) {
+	search := searchFactory("")
+	search.Platform = "aws"
+	search.CloudSvcs = []string{"ec2", "elbv2", "natgw", "eni"}
+
+	var tests = []struct {
+		ipamCloudSvc           string
+		expectedEnrichmentSvcs []string
+	}{
+		{"ec2", []string{"ec2"}},
+		{"natgw", []string{"natgw"}},
+		{"vpc", []string{"ec2", "elbv2", "natgw", "eni"}},
+		{"rds", nil},
+		{"lambda", nil},
+		{"ipam", nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.ipamCloudSvc, func(t *testing.T) {
+			res := search.GetIPAMEnrichmentSvcs(generalResource.Resource{CloudSvc: td.ipamCloudSvc})
+			if !slices.Equal(res, td.expectedEnrichmentSvcs) {
+				t.Errorf("Determining IPAM enrichment services failed; expected %v, received %v", td.expectedEnrichmentSvcs, res)
+			}
+		})
+	}
+}
+
+func TestIsSearchedSvc(t *testing.T) {
+	var tests = []struct {
+		testName, cloudSvc string
+		cloudSvcs          []string
+		expectedResult     bool
+	}{
+		{"requestedSvc", "ec2", []string{"ec2", "eni"}, true},
+		{"unrequestedSvc", "elbv2", []string{"ec2", "eni"}, false},
+		{"unpluggedSvcViaENI", "vpce", []string{"ec2", "eni"}, true},
+		{"unpluggedSvcWithoutENI", "vpce", []string{"ec2"}, false},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			search := searchFactory("")
+			search.Platform = "aws"
+			search.CloudSvcs = td.cloudSvcs
+
+			if res := search.IsSearchedSvc(td.cloudSvc); res != td.expectedResult {
+				t.Errorf("Checking if %s is searched failed; expected %t, received %t", td.cloudSvc, td.expectedResult, res)
+			}
+		})
+	}
+}
+
 func TestRunIPFuzzing(t *testing.T) {
 	var tests = ipFactory()
 
@@ -192,7 +374,7 @@ func TestRunIPFuzzing(t *testing.T) {
 		search := searchFactory(td.ipAddr)
 
 		t.Run(testName, func(t *testing.T) {
-			fuzzedSvcSet, err := search.RunIPFuzzing(false)
+			fuzzedSvcSet, _, err := search.RunIPFuzzing(false)
 			if err != nil {
 				t.Errorf("Basic IP fuzzing routine unexpectedly failed; error: %s", err)
 			}
@@ -216,7 +398,7 @@ func TestRunIPFuzzing_AdvancedFuzzing(t *testing.T) {
 		search := searchFactory(td.ipAddr)
 
 		t.Run(testName, func(t *testing.T) {
-			fuzzedSvcSet, err := search.RunIPFuzzing(true)
+			fuzzedSvcSet, _, err := search.RunIPFuzzing(true)
 			if err != nil {
 				t.Errorf("Basic IP fuzzing routine unexpectedly failed; error: %s", err)
 			}
@@ -230,6 +412,54 @@ func TestRunIPFuzzing_AdvancedFuzzing(t *testing.T) {
 	}
 }
 
+func TestGetFuzzedSvcs(t *testing.T) {
+	var tests = []struct {
+		fuzzedSvc          string
+		expectedFuzzedSvcs []string
+	}{
+		{"EC2", []string{"ec2", "ecs", "eip", "elbv1", "elbv2", "lightsail", "natgw", "rds", "redshift", "eni"}},
+		{"CLOUDFRONT", []string{"cloudfront", "apigateway", "lightsail"}},
+		{"API_GATEWAY", []string{"apigateway"}},
+		{"GLOBALACCELERATOR", []string{"globalaccelerator"}},
+		{"ROUTE53_HEALTHCHECKS", nil},
+		{"UNKNOWN", nil},
+		{"", nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.fuzzedSvc, func(t *testing.T) {
+			if fuzzedSvcSet := search.GetFuzzedSvcs(td.fuzzedSvc); !slices.Equal(fuzzedSvcSet, td.expectedFuzzedSvcs) {
+				t.Errorf("Determining services to search from IP fuzzing failed; expected %v, received %v", td.expectedFuzzedSvcs, fuzzedSvcSet)
+			}
+		})
+	}
+}
+
+func TestMergeFuzzedSvcs(t *testing.T) {
+	var tests = []struct {
+		testName           string
+		fuzzedSvcSets      [][]string
+		expectedFuzzedSvcs []string
+	}{
+		{
+			// the fallback eni service is fuzzed for the first IP, but should still be searched last
+			"prioritized",
+			[][]string{search.GetFuzzedSvcs("EC2"), search.GetFuzzedSvcs("CLOUDFRONT")},
+			[]string{"ec2", "ecs", "eip", "elbv1", "elbv2", "lightsail", "natgw", "rds", "redshift", "cloudfront", "apigateway", "eni"},
+		},
+		{"unknownSvc", [][]string{{"cloudfront"}, nil}, nil},
+		{"noIPs", nil, nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			if fuzzedSvcSet := search.MergeFuzzedSvcs("aws", td.fuzzedSvcSets); !slices.Equal(fuzzedSvcSet, td.expectedFuzzedSvcs) {
+				t.Errorf("Merging fuzzed services failed; expected %v, received %v", td.expectedFuzzedSvcs, fuzzedSvcSet)
+			}
+		})
+	}
+}
+
 func TestStartSearch_CloudSvcs(t *testing.T) {
 	var tests = []struct {
 		ipAddr, cloudSvc string
@@ -458,3 +688,36 @@ func TestStartSearch_OrgSearchEnabled_TargetOUID_InvalidID(t *testing.T) {
 		})
 	}
 }
+
+func TestStartBulkSearch(t *testing.T) {
+	var tests = []struct {
+		testName string
+		ipAddrs  []string
+		valid    bool
+	}{
+		{"noIPs", nil, false},
+		{"multipleIPs", []string{"1.1.1.1", "2600:9000:24eb:dc00:1:3b80:4f00:21", "8.8.8.8"}, true},
+	}
+
+	for _, td := range tests {
+		bulkSearch := searchFactory("")
+		bulkSearch.Platform = "aws"
+		bulkSearch.Regions = []string{"us-east-1"}
+
+		t.Run(td.testName, func(t *testing.T) {
+			var resultIPAddrs []string
+
+			err := bulkSearch.StartBulkSearch(td.ipAddrs, "ec2", false, false, false, "", "", "", false, func(result search.BulkResult) {
+				resultIPAddrs = append(resultIPAddrs, result.IpAddr)
+			})
+			if (err == nil) != td.valid {
+				t.Fatalf("Bulk search failed; expected valid: %t, received error: %v", td.valid, err)
+			}
+
+			// every IP should get exactly one result, regardless of whether it was found
+			if td.valid && !slices.Equal(resultIPAddrs, td.ipAddrs) {
+				t.Errorf("Bulk search returned unexpected results; expected one result for each of %v, received %v", td.ipAddrs, resultIPAddrs)
+			}
+		})
+	}
+}
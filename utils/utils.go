@@ -1,8 +1,16 @@
 package utils
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net"
+	"net/netip"
+	"slices"
 	"strings"
 
 	"github.com/rollbar/rollbar-go"
@@ -48,8 +56,205 @@ func DetermineIpAddrVersion(ipAddr string) (int, error) {
 	return ipVer, nil
 }
 
+// FormatTags flattens a list of tags into a map, using kv to read the key and value of each tag since every API models tags with its own type
+func FormatTags[T any](tags []T, kv func(T) (string, string)) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	tagSet := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value := kv(tag)
+		tagSet[key] = value
+	}
+
+	return tagSet
+}
+
 func FormatStrSliceAsCSV(strs []string) string {
 	formattedStr := "[" + strings.Join(strs, ",") + "]"
 
 	return formattedStr
 }
+
+func NormalizeIPAddr(ipAddr string) (string, error) {
+	// cloud APIs return IPs in their canonical form (e.g. compressed IPv6), so user input needs to match
+	parsedIPAddr, err := netip.ParseAddr(strings.TrimSpace(ipAddr))
+	if err != nil {
+		return "", err
+	}
+
+	return parsedIPAddr.Unmap().String(), nil
+}
+
+func ReadIPAddrs(reader io.Reader) ([]string, error) {
+	// IPs can be provided as a JSON array, or as one per line; lines can also be CSV rows, in which case the first column containing an IP is used
+	var rawIPAddrs, ipAddrs []string
+
+	rawInput, err := io.ReadAll(reader)
+	if err != nil {
+		return ipAddrs, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(rawInput), []byte("[")) {
+		err = json.Unmarshal(rawInput, &rawIPAddrs)
+		if err != nil {
+			return ipAddrs, fmt.Errorf("invalid JSON array of IPs provided: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(rawInput))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			csvReader := csv.NewReader(strings.NewReader(line))
+			csvReader.TrimLeadingSpace = true
+			fields, err := csvReader.Read()
+			if err != nil {
+				return ipAddrs, fmt.Errorf("unable to parse line '%s': %w", line, err)
+			}
+
+			var lineIPAddr string
+			for _, field := range fields {
+				if _, err = netip.ParseAddr(strings.TrimSpace(field)); err == nil {
+					lineIPAddr = field
+					break
+				}
+			}
+
+			// lines without an IP (e.g. CSV headers) are skipped
+			if lineIPAddr != "" {
+				rawIPAddrs = append(rawIPAddrs, lineIPAddr)
+			}
+		}
+
+		err = scanner.Err()
+		if err != nil {
+			return ipAddrs, err
+		}
+	}
+
+	seenIPAddrs := map[string]bool{}
+	for _, rawIPAddr := range rawIPAddrs {
+		ipAddr, err := NormalizeIPAddr(rawIPAddr)
+		if err != nil {
+			return ipAddrs, fmt.Errorf("invalid IP provided: '%s'", rawIPAddr)
+		}
+
+		if !seenIPAddrs[ipAddr] {
+			seenIPAddrs[ipAddr] = true
+			ipAddrs = append(ipAddrs, ipAddr)
+		}
+	}
+
+	return ipAddrs, nil
+}
+
+func NormalizeCIDR(cidr string) (string, error) {
+	parsedPrefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+	if err != nil {
+		return "", err
+	}
+
+	return parsedPrefix.Masked().String(), nil
+}
+
+// IPMatcher matches IP addresses against a set of search targets, each of which can either be a single IP or a CIDR block
+type IPMatcher struct {
+	ipAddrTgts map[netip.Addr][]string
+	prefixTgts map[netip.Prefix][]string
+}
+
+func NewIPMatcher(tgts []string) IPMatcher {
+	ipMatcher := IPMatcher{
+		ipAddrTgts: map[netip.Addr][]string{},
+		prefixTgts: map[netip.Prefix][]string{},
+	}
+
+	for _, tgt := range tgts {
+		if strings.Contains(tgt, "/") {
+			parsedPrefix, err := netip.ParsePrefix(tgt)
+			if err == nil {
+				parsedPrefix = parsedPrefix.Masked()
+				ipMatcher.prefixTgts[parsedPrefix] = append(ipMatcher.prefixTgts[parsedPrefix], tgt)
+			}
+		} else {
+			parsedIPAddr, err := netip.ParseAddr(tgt)
+			if err == nil {
+				parsedIPAddr = parsedIPAddr.Unmap()
+				ipMatcher.ipAddrTgts[parsedIPAddr] = append(ipMatcher.ipAddrTgts[parsedIPAddr], tgt)
+			}
+		}
+
+		// invalid targets are ignored since they could never match anything anyways
+	}
+
+	return ipMatcher
+}
+
+// Match returns every search target that the IP address falls under
+func (ipMatcher IPMatcher) Match(ipAddr string) []string {
+	parsedIPAddr, err := netip.ParseAddr(ipAddr)
+	if err != nil {
+		return nil
+	}
+	parsedIPAddr = parsedIPAddr.Unmap()
+
+	matchedTgts := slices.Clone(ipMatcher.ipAddrTgts[parsedIPAddr])
+	for prefix, tgts := range ipMatcher.prefixTgts {
+		if prefix.Contains(parsedIPAddr) {
+			matchedTgts = append(matchedTgts, tgts...)
+		}
+	}
+
+	return matchedTgts
+}
+
+// MatchAny returns every search target that any of the IP addresses fall under, without duplicates
+func (ipMatcher IPMatcher) MatchAny(ipAddrs []string) []string {
+	var matchedTgts []string
+
+	for _, ipAddr := range ipAddrs {
+		for _, tgt := range ipMatcher.Match(ipAddr) {
+			if !slices.Contains(matchedTgts, tgt) {
+				matchedTgts = append(matchedTgts, tgt)
+			}
+		}
+	}
+
+	return matchedTgts
+}
+
+// MatchPrefix returns every search target that falls within the CIDR block, e.g. an IP within a prefix delegated to an interface, or a target CIDR block that overlaps it
+func (ipMatcher IPMatcher) MatchPrefix(cidr string) []string {
+	parsedPrefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil
+	}
+	parsedPrefix = parsedPrefix.Masked()
+
+	var matchedTgts []string
+	for ipAddr, tgts := range ipMatcher.ipAddrTgts {
+		if parsedPrefix.Contains(ipAddr) {
+			matchedTgts = append(matchedTgts, tgts...)
+		}
+	}
+	for prefix, tgts := range ipMatcher.prefixTgts {
+		if prefix.Overlaps(parsedPrefix) {
+			matchedTgts = append(matchedTgts, tgts...)
+		}
+	}
+
+	return matchedTgts
+}
+
+func FormatIPAddrs(ipAddrs []net.IP) []string {
+	var formattedIPAddrs []string
+	for _, ipAddr := range ipAddrs {
+		formattedIPAddrs = append(formattedIPAddrs, ipAddr.String())
+	}
+
+	return formattedIPAddrs
+}
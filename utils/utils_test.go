@@ -6,8 +6,12 @@ package utils_test
 
 import (
 	"fmt"
+	"maps"
+	"strings"
 	"testing"
 
+	"golang.org/x/exp/slices"
+
 	"github.com/magneticstain/ip-2-cloudresource/utils"
 )
 
@@ -104,3 +108,156 @@ func TestDetermineIpAddrVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestReadIPAddrs(t *testing.T) {
+	var tests = []struct {
+		testName, input string
+		expectedIPAddrs []string
+		valid           bool
+	}{
+		{"newlineDelimited", "1.1.1.1\n\n# comment\n2606:2800:0220:0001:0248:1893:25c8:1946\n", []string{"1.1.1.1", "2606:2800:220:1:248:1893:25c8:1946"}, true},
+		{"csvWithHeader", "host,ip,owner\nweb01, 1.1.1.1, alice\ndb01,8.8.8.8,bob\n", []string{"1.1.1.1", "8.8.8.8"}, true},
+		{"jsonArray", ` ["1.1.1.1", "8.8.8.8", "1.1.1.1"]`, []string{"1.1.1.1", "8.8.8.8"}, true},
+		{"invalidIPInJSONArray", `["1.1.1.1", "123.456.789.10"]`, nil, false},
+		{"invalidJSON", `["1.1.1.1",`, nil, false},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			ipAddrs, err := utils.ReadIPAddrs(strings.NewReader(td.input))
+
+			if !td.valid {
+				if err == nil {
+					t.Error("expected error when reading invalid IP list, but none was returned")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error when reading IP list: %s", err)
+			}
+
+			if !slices.Equal(ipAddrs, td.expectedIPAddrs) {
+				t.Errorf("IP list was read incorrectly; expected %v, received %v", td.expectedIPAddrs, ipAddrs)
+			}
+		})
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	type testTag struct {
+		key, value string
+	}
+	kv := func(tag testTag) (string, string) {
+		return tag.key, tag.value
+	}
+
+	var tests = []struct {
+		testName     string
+		tags         []testTag
+		expectedTags map[string]string
+	}{
+		{"tags", []testTag{{"Name", "web01"}, {"env", "prod"}}, map[string]string{"Name": "web01", "env": "prod"}},
+		{"noTags", nil, nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			formattedTags := utils.FormatTags(td.tags, kv)
+
+			if (formattedTags == nil) != (td.expectedTags == nil) || !maps.Equal(formattedTags, td.expectedTags) {
+				t.Errorf("Tags were formatted incorrectly; expected %v, received %v", td.expectedTags, formattedTags)
+			}
+		})
+	}
+}
+
+func TestNormalizeCIDR(t *testing.T) {
+	var tests = []struct {
+		cidr, expectedCIDR string
+		valid              bool
+	}{
+		{"203.0.113.77/24", "203.0.113.0/24", true},
+		{"2600:1f18:0000::1/40", "2600:1f18::/40", true},
+		{"203.0.113.0/33", "", false},
+		{"203.0.113.0", "", false},
+	}
+
+	for _, td := range tests {
+		t.Run(td.cidr, func(t *testing.T) {
+			normalizedCIDR, err := utils.NormalizeCIDR(td.cidr)
+
+			if !td.valid {
+				if err == nil {
+					t.Error("expected error when normalizing invalid CIDR, but none was returned")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("unexpected error when normalizing CIDR: %s", err)
+			}
+
+			if normalizedCIDR != td.expectedCIDR {
+				t.Errorf("CIDR was normalized incorrectly; expected %s, received %s", td.expectedCIDR, normalizedCIDR)
+			}
+		})
+	}
+}
+
+func TestIPMatcher(t *testing.T) {
+	ipMatcher := utils.NewIPMatcher([]string{"1.1.1.1", "1.1.1.0/24", "2600:1f18::/32", "not-an-ip"})
+
+	var tests = []struct {
+		testName         string
+		ipAddrs          []string
+		expectedMatchSet []string
+	}{
+		{"ipAndCIDRMatch", []string{"1.1.1.1"}, []string{"1.1.1.1", "1.1.1.0/24"}},
+		{"cidrOnlyMatch", []string{"1.1.1.2"}, []string{"1.1.1.0/24"}},
+		{"ipv6CIDRMatch", []string{"2600:1f18:0:1::5"}, []string{"2600:1f18::/32"}},
+		{"noMatch", []string{"8.8.8.8", "2001:db8::1"}, nil},
+		{"dedupeAcrossAddrs", []string{"1.1.1.2", "1.1.1.3", "2600:1f18::1"}, []string{"1.1.1.0/24", "2600:1f18::/32"}},
+	}
+
+	for _, td := range tests {
+		t.Run(td.testName, func(t *testing.T) {
+			matchSet := ipMatcher.MatchAny(td.ipAddrs)
+
+			slices.Sort(matchSet)
+			slices.Sort(td.expectedMatchSet)
+			if !slices.Equal(matchSet, td.expectedMatchSet) {
+				t.Errorf("IP matching failed; expected %v, received %v", td.expectedMatchSet, matchSet)
+			}
+		})
+	}
+}
+
+func TestIPMatcher_MatchPrefix(t *testing.T) {
+	ipMatcher := utils.NewIPMatcher([]string{"2600:1f18:1:2::5", "2600:1f18::/32", "10.0.0.0/8"})
+
+	var tests = []struct {
+		cidr             string
+		expectedMatchSet []string
+	}{
+		{"2600:1f18:1:2::/80", []string{"2600:1f18:1:2::5", "2600:1f18::/32"}},
+		{"2600:1f18:1:3::/80", []string{"2600:1f18::/32"}},
+		{"10.1.2.0/28", []string{"10.0.0.0/8"}},
+		{"192.168.0.0/28", nil},
+		{"not-a-cidr", nil},
+	}
+
+	for _, td := range tests {
+		t.Run(td.cidr, func(t *testing.T) {
+			matchSet := ipMatcher.MatchPrefix(td.cidr)
+
+			slices.Sort(matchSet)
+			slices.Sort(td.expectedMatchSet)
+			if !slices.Equal(matchSet, td.expectedMatchSet) {
+				t.Errorf("IP prefix matching failed; expected %v, received %v", td.expectedMatchSet, matchSet)
+			}
+		})
+	}
+}